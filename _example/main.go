@@ -33,6 +33,10 @@ func main() {
 
 	l.Info("TEST", "struct", sampleStruct)
 
+	// Types implementing log.LogValuer control how they are printed,
+	// this will be printed as user.age=45 user.name="Anton".
+	l.Info("TEST", "user", user{Name: "Anton", Age: 45, Password: "secret"})
+
 	// This will also work despite only having a value and not a key.
 	// This will be printed as no_key=sampleStruct.
 	l.Info("TEST", sampleStruct)
@@ -46,6 +50,16 @@ func main() {
 
 	type ctxName string
 	ctx := context.WithValue(context.Background(), ctxName("name"), "anton")
-	l.Info("TEST WITH CTX", "CTX", ctx)                              // will print Context = {}
+	l.Info("TEST WITH CTX", "CTX", ctx)                              // will print the context's String()
 	l.Info("TEST WITH CTX VALUE", "CTX", ctx.Value(ctxName("name"))) // will print CTX = anton
 }
+
+type user struct {
+	Name     string
+	Age      int
+	Password string
+}
+
+func (u user) LogValue() interface{} {
+	return log.Group("name", u.Name, "age", u.Age)
+}
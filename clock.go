@@ -0,0 +1,75 @@
+package log
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+func FixedClock(t time.Time) func() time.Time {
+	return func() time.Time {
+		return t
+	}
+}
+
+// MonotonicClock returns a clock starting at start which advances by step
+// on every call.
+func MonotonicClock(start time.Time, step time.Duration) func() time.Time {
+	var calls atomic.Int64
+
+	return func() time.Time {
+		return start.Add(time.Duration(calls.Add(1)-1) * step)
+	}
+}
+
+func (l *Logger) SetClock(clock func() time.Time) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clock = clock
+}
+
+func (l *Logger) SetColor(enabled bool) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.noColor = !enabled
+}
+
+// savedOutput holds the settings replaced by SetDeterministic.
+type savedOutput struct {
+	clock   func() time.Time
+	noColor bool
+}
+
+// SetDeterministic makes output reproducible for golden files by using a
+// monotonic clock starting at the Unix epoch and disabling colors. Disabling
+// it restores the clock and color setting from before it was enabled.
+func (l *Logger) SetDeterministic(enabled bool) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if enabled {
+		if r.saved == nil {
+			r.saved = &savedOutput{clock: r.clock, noColor: r.noColor}
+		}
+
+		r.clock = MonotonicClock(time.Unix(0, 0).UTC(), time.Second)
+		r.noColor = true
+
+		return
+	}
+
+	if r.saved == nil {
+		return
+	}
+
+	r.clock = r.saved.clock
+	r.noColor = r.saved.noColor
+	r.saved = nil
+}
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedClock(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	clock := FixedClock(ts)
+
+	require.Equal(t, ts, clock())
+	require.Equal(t, ts, clock())
+}
+
+func TestMonotonicClock(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	clock := MonotonicClock(ts, time.Second)
+
+	require.Equal(t, ts, clock())
+	require.Equal(t, ts.Add(time.Second), clock())
+	require.Equal(t, ts.Add(2*time.Second), clock())
+}
+
+func TestLoggerSetClock(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetClock(FixedClock(time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)))
+
+	_, _ = l.Info("test")
+
+	require.Contains(t, buf.String(), "timestamp=2024-05-06T12:00:00Z")
+}
+
+func TestLoggerSetColor(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+
+	t.Run("color enabled by default", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Info("test")
+		require.Contains(t, buf.String(), formatLevel(LevelInfo))
+	})
+
+	t.Run("color disabled", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetColor(false)
+		_, _ = l.Info("test")
+		require.Contains(t, buf.String(), "level=INF ")
+	})
+}
+
+func TestLoggerSetDeterministic(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetDeterministic(true)
+
+	_, _ = l.Info("first", "b", 2, "a", 1)
+	_, _ = l.Info("second")
+
+	expected := `timestamp=1970-01-01T00:00:00Z level=INF msg="first" a=1 b=2
+timestamp=1970-01-01T00:00:01Z level=INF msg="second"
+`
+	require.Equal(t, expected, buf.String())
+
+	l.SetDeterministic(false)
+	require.Nil(t, l.clock)
+	require.False(t, l.noColor)
+
+	t.Run("restores previous settings", func(t *testing.T) {
+		l := NewLogger()
+		buf := &bytes.Buffer{}
+		l.SetOut(buf)
+
+		ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		l.SetClock(FixedClock(ts))
+		l.SetColor(false)
+
+		l.SetDeterministic(true)
+		l.SetDeterministic(true)
+		l.SetDeterministic(false)
+
+		require.True(t, l.noColor)
+		require.Equal(t, ts, l.clock())
+
+		l.SetDeterministic(false)
+		require.True(t, l.noColor)
+		require.Equal(t, ts, l.clock())
+	})
+}
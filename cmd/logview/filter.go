@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devusSs/log"
+)
+
+type filter struct {
+	level   log.Level
+	since   time.Time
+	until   time.Time
+	message *regexp.Regexp
+	where   []predicate
+}
+
+func (f *filter) match(r log.Record) bool {
+	if r.Level < f.level {
+		return false
+	}
+
+	if !f.since.IsZero() && r.Time.Before(f.since) {
+		return false
+	}
+
+	if !f.until.IsZero() && r.Time.After(f.until) {
+		return false
+	}
+
+	if f.message != nil && !f.message.MatchString(r.Message) {
+		return false
+	}
+
+	for _, p := range f.where {
+		if !p.match(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// predicate compares a field against a value, e.g. status>=500. Fields in
+// groups are addressed by their dotted key.
+type predicate struct {
+	key   string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+var predicateOps []string = []string{">=", "<=", "!=", "=~", "=", ">", "<"}
+
+func parsePredicate(s string) (predicate, error) {
+	for i := range s {
+		for _, op := range predicateOps {
+			if !strings.HasPrefix(s[i:], op) {
+				continue
+			}
+
+			p := predicate{key: strings.TrimSpace(s[:i]), op: op, value: strings.TrimSpace(s[i+len(op):])}
+			if p.key == "" {
+				return p, fmt.Errorf("missing field in predicate %q", s)
+			}
+
+			if op == "=~" {
+				re, err := regexp.Compile(p.value)
+				if err != nil {
+					return p, err
+				}
+
+				p.re = re
+			}
+
+			return p, nil
+		}
+	}
+
+	return predicate{}, fmt.Errorf("missing operator in predicate %q", s)
+}
+
+func (p predicate) match(r log.Record) bool {
+	v, ok := lookupField(r, p.key)
+	if !ok {
+		return p.op == "!="
+	}
+
+	got := fmt.Sprintf("%v", v)
+
+	if p.op == "=~" {
+		return p.re.MatchString(got)
+	}
+
+	cmp := strings.Compare(got, p.value)
+
+	a, errA := strconv.ParseFloat(got, 64)
+	b, errB := strconv.ParseFloat(p.value, 64)
+
+	if errA == nil && errB == nil {
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		default:
+			cmp = 0
+		}
+	}
+
+	switch p.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+func lookupField(r log.Record, key string) (interface{}, bool) {
+	switch key {
+	case "msg":
+		return r.Message, true
+	case "logger":
+		return r.Logger, r.Logger != ""
+	}
+
+	if v, ok := r.Fields[key]; ok {
+		return v, true
+	}
+
+	var current interface{} = r.Fields
+
+	for _, part := range strings.Split(key, ".") {
+		var m map[string]interface{}
+
+		switch group := current.(type) {
+		case log.Fields:
+			m = group
+		case map[string]interface{}:
+			m = group
+		default:
+			return nil, false
+		}
+
+		var ok bool
+
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
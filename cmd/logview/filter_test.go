@@ -0,0 +1,85 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/devusSs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePredicate(t *testing.T) {
+	p, err := parsePredicate("status>=500")
+	require.NoError(t, err)
+	require.Equal(t, predicate{key: "status", op: ">=", value: "500"}, p)
+
+	p, err = parsePredicate("path =~ ^/api")
+	require.NoError(t, err)
+	require.Equal(t, "path", p.key)
+	require.NotNil(t, p.re)
+
+	_, err = parsePredicate("status")
+	require.Error(t, err)
+
+	_, err = parsePredicate("=500")
+	require.Error(t, err)
+
+	_, err = parsePredicate("path=~[")
+	require.Error(t, err)
+}
+
+func TestPredicateMatch(t *testing.T) {
+	r := log.Record{
+		Message: "request",
+		Logger:  "http",
+		Fields: map[string]interface{}{
+			"status": 503,
+			"path":   "/api/users",
+			"req":    map[string]interface{}{"id": "a"},
+			"db.ms":  12.5,
+		},
+	}
+
+	cases := []struct {
+		Predicate string
+		Want      bool
+	}{
+		{Predicate: "status>=500", Want: true},
+		{Predicate: "status>503", Want: false},
+		{Predicate: "status=503", Want: true},
+		{Predicate: "status!=503", Want: false},
+		{Predicate: "status<1000", Want: true},
+		{Predicate: "status<=400", Want: false},
+		{Predicate: "path=~^/api/", Want: true},
+		{Predicate: "path=/api", Want: false},
+		{Predicate: "req.id=a", Want: true},
+		{Predicate: "db.ms>10", Want: true},
+		{Predicate: "logger=http", Want: true},
+		{Predicate: "msg=~req", Want: true},
+		{Predicate: "missing=1", Want: false},
+		{Predicate: "missing!=1", Want: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Predicate, func(t *testing.T) {
+			p, err := parsePredicate(c.Predicate)
+			require.NoError(t, err)
+			require.Equal(t, c.Want, p.match(r))
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	r := log.Record{Time: ts, Level: log.LevelWarn, Message: "slow query"}
+
+	require.True(t, (&filter{}).match(r))
+	require.True(t, (&filter{level: log.LevelWarn}).match(r))
+	require.False(t, (&filter{level: log.LevelError}).match(r))
+	require.True(t, (&filter{since: ts, until: ts}).match(r))
+	require.False(t, (&filter{since: ts.Add(time.Second)}).match(r))
+	require.False(t, (&filter{until: ts.Add(-time.Second)}).match(r))
+	require.True(t, (&filter{message: regexp.MustCompile("slow")}).match(r))
+	require.False(t, (&filter{message: regexp.MustCompile("^query")}).match(r))
+}
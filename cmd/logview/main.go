@@ -0,0 +1,222 @@
+// Command logview pretty-prints, filters and converts logs written by the
+// TextHandler or JSONHandler of github.com/devusSs/log.
+//
+//	logview [flags] [file ...]
+//
+// Input is read from the given files or stdin. Lines which can not be
+// decoded are printed unchanged in pretty output and reported on stderr
+// when converting.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/devusSs/log"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type predicates []predicate
+
+func (p *predicates) String() string {
+	return fmt.Sprintf("%v", *p)
+}
+
+func (p *predicates) Set(s string) error {
+	pred, err := parsePredicate(s)
+	if err != nil {
+		return err
+	}
+
+	*p = append(*p, pred)
+
+	return nil
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("logview", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		where predicates
+
+		output = fs.String("o", "pretty", "output format: pretty, json or logfmt")
+		level  = fs.String("level", "debug", "minimum level")
+		since  = fs.String("since", "", "only records at or after this RFC 3339 time or duration ago")
+		until  = fs.String("until", "", "only records at or before this RFC 3339 time or duration ago")
+		grep   = fs.String("grep", "", "only records whose message matches this regular expression")
+		color  = fs.String("color", "auto", "colorize pretty output: auto, always or never")
+		theme  = fs.String("theme", "dark", "color theme: dark or light")
+	)
+
+	fs.Var(&where, "where", "only records matching a field predicate like status>=500, repeatable\n"+
+		"operators: = != > >= < <= =~ (regular expression)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	f := &filter{where: where}
+
+	var err error
+
+	if f.level, err = log.ParseLevel(*level); err != nil {
+		fmt.Fprintf(stderr, "logview: invalid level %q\n", *level)
+		return 2
+	}
+
+	if f.since, err = parseTime(*since); err != nil {
+		fmt.Fprintf(stderr, "logview: invalid -since: %v\n", err)
+		return 2
+	}
+
+	if f.until, err = parseTime(*until); err != nil {
+		fmt.Fprintf(stderr, "logview: invalid -until: %v\n", err)
+		return 2
+	}
+
+	if *grep != "" {
+		if f.message, err = regexp.Compile(*grep); err != nil {
+			fmt.Fprintf(stderr, "logview: invalid -grep: %v\n", err)
+			return 2
+		}
+	}
+
+	l := log.NewLogger()
+	l.SetOut(stdout)
+	l.SetLevel(log.LevelDebug)
+
+	switch *output {
+	case "pretty":
+		l.SetHandler(log.ConsoleHandler)
+	case "json":
+		l.SetHandler(log.JSONHandler)
+	case "logfmt":
+		l.SetHandler(log.TextHandler)
+	default:
+		fmt.Fprintf(stderr, "logview: invalid output format %q\n", *output)
+		return 2
+	}
+
+	switch *theme {
+	case "dark":
+		l.SetTheme(log.DarkTheme())
+	case "light":
+		l.SetTheme(log.LightTheme())
+	default:
+		fmt.Fprintf(stderr, "logview: invalid theme %q\n", *theme)
+		return 2
+	}
+
+	switch *color {
+	case "always":
+	case "never":
+		l.SetColor(false)
+	case "auto":
+		l.SetColor(*output == "pretty" && isTerminal(stdout))
+	default:
+		fmt.Fprintf(stderr, "logview: invalid color mode %q\n", *color)
+		return 2
+	}
+
+	v := &viewer{logger: l, filter: f, pretty: *output == "pretty", stdout: stdout, stderr: stderr}
+
+	if fs.NArg() == 0 {
+		return v.view("<stdin>", stdin)
+	}
+
+	status := 0
+
+	for _, name := range fs.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "logview: %v\n", err)
+			status = 1
+
+			continue
+		}
+
+		if s := v.view(name, file); s != 0 {
+			status = s
+		}
+
+		_ = file.Close()
+	}
+
+	return status
+}
+
+type viewer struct {
+	logger *log.Logger
+	filter *filter
+	pretty bool
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (v *viewer) view(name string, r io.Reader) int {
+	reader := log.NewReader(r)
+
+	for {
+		record, err := reader.Read()
+
+		var parseErr *log.ParseError
+
+		switch {
+		case err == io.EOF:
+			return 0
+		case errors.As(err, &parseErr):
+			if v.pretty {
+				fmt.Fprintln(v.stdout, parseErr.Text)
+			} else {
+				fmt.Fprintf(v.stderr, "logview: %s:%d: %v\n", name, parseErr.Line, parseErr.Err)
+			}
+
+			continue
+		case err != nil:
+			fmt.Fprintf(v.stderr, "logview: %s: %v\n", name, err)
+			return 1
+		}
+
+		if !v.filter.match(record) {
+			continue
+		}
+
+		if _, err := v.logger.WriteRecord(record); err != nil {
+			fmt.Fprintf(v.stderr, "logview: %v\n", err)
+			return 1
+		}
+	}
+}
+
+// parseTime parses an RFC 3339 time or a duration before now.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Args   []string
+		Stdout string
+		Stderr string
+	}{
+		{
+			Name: "convert to logfmt",
+			Args: []string{"-o", "logfmt", "-level", "info", "testdata/app.log"},
+			Stdout: `timestamp=2024-05-06T12:00:00Z level=INF logger=http msg="request" path="/" status=200
+timestamp=2024-05-06T12:00:01Z level=ERR logger=http msg="request" path="/broken" status=500
+timestamp=2024-05-06T12:00:02Z level=WRN logger=db msg="slow query" ms=250 query="select * from users"
+`,
+			Stderr: "logview: testdata/app.log:5: malformed log line\n",
+		},
+		{
+			Name: "convert to json with filters",
+			Args: []string{"-o", "json", "-where", "status>=500", "-grep", "^req", "testdata/app.log"},
+			Stdout: `{"timestamp":"2024-05-06T12:00:01Z","level":"err","logger":"http","msg":"request","path":"/broken","status":500}
+`,
+			Stderr: "logview: testdata/app.log:5: malformed log line\n",
+		},
+		{
+			Name: "time range",
+			Args: []string{
+				"-o", "logfmt",
+				"-since", "2024-05-06T12:00:02Z",
+				"-until", "2024-05-06T12:00:03Z",
+				"-where", "req.id=7",
+				"testdata/app.log",
+			},
+			Stdout: `timestamp=2024-05-06T12:00:03Z level=DBG msg="cache miss" key="user:1" req.id=7
+`,
+			Stderr: "logview: testdata/app.log:5: malformed log line\n",
+		},
+		{
+			Name:   "missing file",
+			Args:   []string{"testdata/missing.log"},
+			Stderr: "logview: open testdata/missing.log: no such file or directory\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+			run(c.Args, strings.NewReader(""), stdout, stderr)
+			require.Equal(t, c.Stdout, stdout.String())
+			require.Equal(t, c.Stderr, stderr.String())
+		})
+	}
+}
+
+func TestRunGroupedJSON(t *testing.T) {
+	stdin := `{"timestamp":"2024-05-06T12:00:00Z","level":"inf","msg":"req","http":{"method":"GET","status":200}}
+{"timestamp":"2024-05-06T12:00:01Z","level":"inf","msg":"req","http":{"method":"GET","status":500}}
+`
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	require.Zero(t, run([]string{"-o", "logfmt", "-where", "http.status>=500"}, strings.NewReader(stdin), stdout, stderr))
+	require.Equal(t, `timestamp=2024-05-06T12:00:01Z level=INF msg="req" http.method="GET" http.status=500
+`, stdout.String())
+	require.Empty(t, stderr.String())
+}
+
+func TestRunPretty(t *testing.T) {
+	in := strings.NewReader(`{"timestamp":"2024-05-06T12:00:00Z","level":"wrn","msg":"test","n":1}
+not a log line
+`)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	require.Zero(t, run([]string{"-color", "never"}, in, stdout, stderr))
+	require.Empty(t, stderr.String())
+
+	ts := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC).Local().Format("15:04:05.000")
+	require.Equal(t, ts+" WRN test"+strings.Repeat(" ", 36)+" n=1\nnot a log line\n", stdout.String())
+
+	stdout.Reset()
+
+	in = strings.NewReader(`level=INF msg="test"` + "\n")
+	require.Zero(t, run([]string{"-color", "always", "-theme", "light"}, in, stdout, stderr))
+	require.Contains(t, stdout.String(), "\033[38;5;25mINF")
+}
+
+func TestRunInvalidFlags(t *testing.T) {
+	cases := [][]string{
+		{"-o", "xml"},
+		{"-level", "verbose"},
+		{"-since", "yesterday"},
+		{"-until", "tomorrow"},
+		{"-grep", "["},
+		{"-where", "status"},
+		{"-color", "sometimes"},
+		{"-theme", "blue"},
+	}
+
+	for _, args := range cases {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			stderr := &bytes.Buffer{}
+
+			require.Equal(t, 2, run(args, strings.NewReader(""), &bytes.Buffer{}, stderr))
+			require.NotEmpty(t, stderr.String())
+		})
+	}
+}
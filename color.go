@@ -2,30 +2,250 @@ package log
 
 import (
 	"fmt"
+	"strings"
 )
 
 var noColor bool = false
 
-type color string
+// Style is an ANSI escape sequence applied to a part of the output. Styles
+// can be combined by chaining, e.g. Color256(208).Bold().
+type Style string
 
 const (
-	colorWhite  color = "\033[37m"
-	colorCyan   color = "\033[36m"
-	colorYellow color = "\033[33m"
-	colorRed    color = "\033[31m"
-	colorReset  color = "\033[0m"
+	StyleNone      Style = ""
+	StyleBold      Style = "\033[1m"
+	StyleDim       Style = "\033[2m"
+	StyleUnderline Style = "\033[4m"
 )
 
-func colorString(s string, c color) string {
+const (
+	colorWhite  Style = "\033[37m"
+	colorCyan   Style = "\033[36m"
+	colorYellow Style = "\033[33m"
+	colorRed    Style = "\033[31m"
+	colorGreen  Style = "\033[32m"
+	colorBlue   Style = "\033[34m"
+	colorBold   Style = StyleBold
+	colorDim    Style = StyleDim
+	colorReset  Style = "\033[0m"
+)
+
+// Color16 returns one of the 16 basic terminal colors, 8 to 15 being the
+// bright variants of 0 to 7.
+func Color16(c uint8) Style {
+	if c >= 8 {
+		return Style(fmt.Sprintf("\033[%dm", 90+c%8))
+	}
+
+	return Style(fmt.Sprintf("\033[%dm", 30+c))
+}
+
+func Color256(c uint8) Style {
+	return Style(fmt.Sprintf("\033[38;5;%dm", c))
+}
+
+// RGB returns a 24-bit truecolor foreground.
+func RGB(r, g, b uint8) Style {
+	return Style(fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b))
+}
+
+func (s Style) Bold() Style {
+	return s + StyleBold
+}
+
+func (s Style) Dim() Style {
+	return s + StyleDim
+}
+
+func (s Style) Underline() Style {
+	return s + StyleUnderline
+}
+
+func (s Style) paint(str string) string {
+	if s == StyleNone || s == colorReset {
+		return str
+	}
+
+	return string(s) + str + string(colorReset)
+}
+
+// Theme configures the colors of the TextHandler and ConsoleHandler.
+type Theme struct {
+	// Levels maps levels to their style, LevelInvalid is used for levels
+	// missing from the map.
+	Levels map[Level]Style
+
+	Timestamp Style
+	Logger    Style
+	Message   Style
+	Key       Style
+
+	String Style
+	Number Style
+	Bool   Style
+	Nil    Style
+	Other  Style
+
+	// Error is used for error values and the keys "error" and "err", and
+	// for messages logged at LevelError or above.
+	Error Style
+}
+
+// DarkTheme is the default theme of the ConsoleHandler.
+func DarkTheme() *Theme {
+	return &Theme{
+		Levels: map[Level]Style{
+			LevelInvalid: colorWhite,
+			LevelDebug:   colorWhite,
+			LevelInfo:    colorCyan,
+			LevelWarn:    colorYellow,
+			LevelError:   colorRed,
+			LevelFatal:   colorRed.Bold(),
+		},
+		Timestamp: colorDim,
+		Logger:    colorBold,
+		Key:       colorDim,
+		String:    colorGreen,
+		Number:    colorBlue,
+		Bool:      colorYellow,
+		Nil:       colorDim,
+		Error:     colorRed,
+	}
+}
+
+// LightTheme uses darker 256-colors readable on light backgrounds.
+func LightTheme() *Theme {
+	return &Theme{
+		Levels: map[Level]Style{
+			LevelInvalid: Color256(240),
+			LevelDebug:   Color256(240),
+			LevelInfo:    Color256(25),
+			LevelWarn:    Color256(130),
+			LevelError:   Color256(160),
+			LevelFatal:   Color256(160).Bold().Underline(),
+		},
+		Timestamp: Color256(244),
+		Logger:    Color256(236).Bold(),
+		Key:       Color256(244),
+		String:    Color256(28),
+		Number:    Color256(25),
+		Bool:      Color256(130),
+		Nil:       Color256(244),
+		Error:     Color256(160),
+	}
+}
+
+// textTheme only colors the level, which is what the TextHandler does if no
+// theme is set.
+var textTheme *Theme = &Theme{
+	Levels: map[Level]Style{
+		LevelInvalid: colorWhite,
+		LevelDebug:   colorWhite,
+		LevelInfo:    colorCyan,
+		LevelWarn:    colorYellow,
+		LevelError:   colorRed,
+		LevelFatal:   colorRed,
+	},
+}
+
+// SetTheme sets the theme of the TextHandler and ConsoleHandler. If nil,
+// the TextHandler only colors levels and the ConsoleHandler uses DarkTheme.
+func (l *Logger) SetTheme(t *Theme) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.theme = t
+}
+
+func (l *Logger) GetTheme() *Theme {
+	r := l.root()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.theme
+}
+
+var (
+	consoleTheme *Theme = DarkTheme()
+	plainTheme   *Theme = &Theme{}
+)
+
+// themeFor returns the theme used by handler, which is plainTheme if colors
+// are disabled. l must be the root logger with its lock held.
+func (l *Logger) themeFor(handler Handler) *Theme {
+	switch {
+	case noColor || l.noColor:
+		return plainTheme
+	case l.theme != nil:
+		return l.theme
+	case handler == ConsoleHandler:
+		return consoleTheme
+	default:
+		return textTheme
+	}
+}
+
+func (t *Theme) level(l Level) Style {
+	if s, ok := t.Levels[l]; ok {
+		return s
+	}
+
+	return t.Levels[LevelInvalid]
+}
+
+func (t *Theme) message(l Level) Style {
+	if l >= LevelError && t.Error != StyleNone {
+		return t.Error
+	}
+
+	return t.Message
+}
+
+// field returns the style of the value v logged under key.
+func (t *Theme) field(key string, v interface{}) Style {
+	if key == "error" || key == "err" {
+		return t.Error
+	}
+
+	return t.value(v)
+}
+
+func (t *Theme) value(v interface{}) Style {
+	switch v.(type) {
+	case nil:
+		return t.Nil
+	case error:
+		return t.Error
+	case bool:
+		return t.Bool
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return t.Number
+	case string:
+		return t.String
+	default:
+		return t.Other
+	}
+}
+
+func colorString(s string, c Style) string {
 	if noColor {
 		return s
 	}
 
 	// TODO: Find way to check if we can color this
 
-	if c == colorReset {
-		return s
+	return c.paint(s)
+}
+
+func formatLevel(l Level) string {
+	level := strings.ToUpper(l.String())
+
+	if noColor {
+		return level
 	}
 
-	return fmt.Sprintf("%s%s%s", c, s, colorReset)
+	return textTheme.level(l).paint(level)
 }
@@ -1,6 +1,8 @@
 package log
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -11,7 +13,7 @@ func TestColorString(t *testing.T) {
 	cases := []struct {
 		Color string
 		S     string
-		C     color
+		C     Style
 		Want  string
 	}{
 		{
@@ -56,3 +58,110 @@ func TestColorString(t *testing.T) {
 		require.Equal(t, colorString("test", colorCyan), "test")
 	})
 }
+
+func TestStyles(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Style Style
+		Want  string
+	}{
+		{Name: "color16", Style: Color16(1), Want: "\033[31m"},
+		{Name: "color16 bright", Style: Color16(9), Want: "\033[91m"},
+		{Name: "color256", Style: Color256(208), Want: "\033[38;5;208m"},
+		{Name: "truecolor", Style: RGB(255, 128, 0), Want: "\033[38;2;255;128;0m"},
+		{Name: "bold", Style: Color256(1).Bold(), Want: "\033[38;5;1m\033[1m"},
+		{Name: "dim underline", Style: StyleNone.Dim().Underline(), Want: "\033[2m\033[4m"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			require.Equal(t, c.Want, string(c.Style))
+			require.Equal(t, c.Want+"test"+string(colorReset), c.Style.paint("test"))
+		})
+	}
+
+	t.Run("none", func(t *testing.T) {
+		require.Equal(t, "test", StyleNone.paint("test"))
+	})
+}
+
+func TestLoggerSetTheme(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	require.Nil(t, l.GetTheme())
+
+	theme := &Theme{
+		Levels:    map[Level]Style{LevelInvalid: Color256(1)},
+		Timestamp: StyleDim,
+		Logger:    StyleBold,
+		Message:   StyleUnderline,
+		Key:       Color256(2),
+		String:    Color256(3),
+		Number:    RGB(1, 2, 3),
+		Error:     Color16(9),
+	}
+
+	l.Named("db").SetTheme(theme)
+	require.Same(t, theme, l.GetTheme())
+
+	t.Run("text handler", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Named("db").Warn("test", "key", "value", "n", 1)
+		out := buf.String()
+
+		require.Contains(t, out, "timestamp="+string(StyleDim))
+		require.Contains(t, out, "level="+Color256(1).paint("WRN"))
+		require.Contains(t, out, "logger="+StyleBold.paint("db"))
+		require.Contains(t, out, `msg="`+StyleUnderline.paint("test")+`"`)
+		require.Contains(t, out, Color256(2).paint("key")+"="+Color256(3).paint(`"value"`))
+		require.Contains(t, out, Color256(2).paint("n")+"="+RGB(1, 2, 3).paint("1"))
+	})
+
+	t.Run("error message", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Error("failed", "err", "timeout")
+		require.Contains(t, buf.String(), `msg="`+Color16(9).paint("failed")+`"`)
+		require.Contains(t, buf.String(), "="+Color16(9).paint(`"timeout"`))
+	})
+
+	t.Run("error value", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Info("failed", "cause", errors.New("boom"))
+		require.Contains(t, buf.String(), Color256(2).paint("cause")+"="+Color16(9).paint(`"boom"`))
+	})
+
+	t.Run("console handler", func(t *testing.T) {
+		defer buf.Reset()
+		defer l.SetHandler(TextHandler)
+
+		l.SetHandler(ConsoleHandler)
+		l.SetTheme(LightTheme())
+
+		_, _ = l.Info("test", "key", "value")
+		require.Contains(t, buf.String(), Color256(25).paint("INF"))
+		require.Contains(t, buf.String(), Color256(244).paint("key=")+Color256(28).paint("value"))
+	})
+
+	t.Run("color disabled", func(t *testing.T) {
+		defer buf.Reset()
+		defer l.SetColor(true)
+
+		l.SetColor(false)
+
+		_, _ = l.Info("test", "key", "value")
+		require.NotContains(t, buf.String(), "\033[")
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetTheme(nil)
+
+		_, _ = l.Info("test", "key", "value")
+		require.Contains(t, buf.String(), "level="+formatLevel(LevelInfo)+` msg="test" key="value"`)
+	})
+}
@@ -0,0 +1,189 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidLevelSpec error = errors.New("invalid level spec")
+
+func (l *Logger) Named(name string) *Logger {
+	name = strings.Trim(name, ".")
+	if name == "" {
+		return l
+	}
+
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	c := l.child()
+	c.name = name
+
+	return c
+}
+
+func (l *Logger) Name() string {
+	return l.name
+}
+
+func (l *Logger) SetComponentLevel(name string, level Level) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if level <= LevelInvalid || level > LevelFatal {
+		delete(r.components, name)
+		return
+	}
+
+	if r.components == nil {
+		r.components = make(map[string]Level)
+	}
+
+	r.components[name] = level
+}
+
+func (l *Logger) ComponentLevels() map[string]Level {
+	r := l.root()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	components := make(map[string]Level, len(r.components))
+	for name, level := range r.components {
+		components[name] = level
+	}
+
+	return components
+}
+
+func (l *Logger) SetLevelSpec(spec string) error {
+	s, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	l.ApplyLevelSpec(s)
+
+	return nil
+}
+
+func (l *Logger) ApplyLevelSpec(s LevelSpec) {
+	r := l.root()
+
+	if s.Level != LevelInvalid {
+		r.SetLevel(s.Level)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.components = make(map[string]Level, len(s.Components))
+	for name, level := range s.Components {
+		r.components[name] = level
+	}
+}
+
+func (l *Logger) GetLevelSpec() LevelSpec {
+	return LevelSpec{
+		Level:      l.root().GetLevel(),
+		Components: l.ComponentLevels(),
+	}
+}
+
+func (l *Logger) levelFor(name string) Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for name != "" {
+		if level, ok := l.components[name]; ok {
+			return level
+		}
+
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+
+		name = name[:i]
+	}
+
+	return l.level
+}
+
+// LevelSpec holds a default level and per component overrides,
+// usually parsed from a string like "info,db=debug,http.client=warn".
+type LevelSpec struct {
+	Level      Level
+	Components map[string]Level
+}
+
+func ParseLevelSpec(spec string) (LevelSpec, error) {
+	s := LevelSpec{
+		Level:      LevelInvalid,
+		Components: make(map[string]Level),
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, levelStr, ok := strings.Cut(part, "=")
+		if !ok {
+			level, err := ParseLevel(part)
+			if err != nil {
+				return LevelSpec{}, fmt.Errorf("%w: %q", ErrInvalidLevelSpec, part)
+			}
+
+			s.Level = level
+			continue
+		}
+
+		name = strings.Trim(strings.TrimSpace(name), ".")
+		level, err := ParseLevel(strings.TrimSpace(levelStr))
+		if name == "" || err != nil {
+			return LevelSpec{}, fmt.Errorf("%w: %q", ErrInvalidLevelSpec, part)
+		}
+
+		s.Components[name] = level
+	}
+
+	return s, nil
+}
+
+func (s LevelSpec) String() string {
+	parts := make([]string, 0, len(s.Components)+1)
+
+	if s.Level != LevelInvalid {
+		parts = append(parts, levelName(s.Level))
+	}
+
+	names := make([]string, 0, len(s.Components))
+	for name := range s.Components {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, levelName(s.Components[name])))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func levelName(level Level) string {
+	for name, l := range levelStrings {
+		if l == level {
+			return name
+		}
+	}
+
+	return level.String()
+}
@@ -0,0 +1,103 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerNamed(t *testing.T) {
+	l := NewLogger()
+
+	t.Run("empty name returns same logger", func(t *testing.T) {
+		require.Equal(t, l, l.Named(""))
+	})
+
+	t.Run("names are hierarchical", func(t *testing.T) {
+		require.Equal(t, "db", l.Named("db").Name())
+		require.Equal(t, "db.pool", l.Named("db").Named("pool").Name())
+	})
+
+	t.Run("named logger shares root config", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		l.SetOut(buf)
+		l.SetHandler(JSONHandler)
+
+		n, err := l.Named("db").Info("test")
+		require.NoError(t, err)
+		require.NotZero(t, n)
+		require.Contains(t, buf.String(), `"logger":"db"`)
+	})
+}
+
+func TestLoggerComponentLevels(t *testing.T) {
+	l := NewLogger()
+	db := l.Named("db")
+	pool := db.Named("pool")
+	http := l.Named("http")
+
+	t.Run("inherit root level", func(t *testing.T) {
+		require.Equal(t, LevelInfo, pool.GetLevel())
+	})
+
+	t.Run("override inherited by children", func(t *testing.T) {
+		db.SetLevel(LevelDebug)
+
+		require.Equal(t, LevelDebug, db.GetLevel())
+		require.Equal(t, LevelDebug, pool.GetLevel())
+		require.Equal(t, LevelInfo, http.GetLevel())
+		require.Equal(t, LevelInfo, l.GetLevel())
+
+		b, _ := pool.Debug("test")
+		require.NotZero(t, b)
+
+		b, _ = http.Debug("test")
+		require.Zero(t, b)
+	})
+
+	t.Run("invalid level removes override", func(t *testing.T) {
+		db.SetLevel(LevelInvalid)
+
+		require.Equal(t, LevelInfo, pool.GetLevel())
+		require.Empty(t, l.ComponentLevels())
+	})
+}
+
+func TestParseLevelSpec(t *testing.T) {
+	t.Run("valid spec", func(t *testing.T) {
+		s, err := ParseLevelSpec("info, db=debug,http.client=warn")
+		require.NoError(t, err)
+		require.Equal(t, LevelInfo, s.Level)
+		require.Equal(t, map[string]Level{"db": LevelDebug, "http.client": LevelWarn}, s.Components)
+		require.Equal(t, "info,db=debug,http.client=warn", s.String())
+	})
+
+	t.Run("no default level", func(t *testing.T) {
+		s, err := ParseLevelSpec("db=error")
+		require.NoError(t, err)
+		require.Equal(t, LevelInvalid, s.Level)
+		require.Equal(t, "db=error", s.String())
+	})
+
+	cases := []string{"verbose", "db=loud", "=debug"}
+
+	for _, c := range cases {
+		t.Run("invalid "+c, func(t *testing.T) {
+			_, err := ParseLevelSpec(c)
+			require.ErrorIs(t, err, ErrInvalidLevelSpec)
+		})
+	}
+}
+
+func TestLoggerSetLevelSpec(t *testing.T) {
+	l := NewLogger()
+
+	require.NoError(t, l.SetLevelSpec("warn,db=debug"))
+	require.Equal(t, LevelWarn, l.GetLevel())
+	require.Equal(t, LevelDebug, l.Named("db").Named("pool").GetLevel())
+	require.Equal(t, "warn,db=debug", l.GetLevelSpec().String())
+
+	require.Error(t, l.SetLevelSpec("db=nope"))
+	require.Equal(t, "warn,db=debug", l.GetLevelSpec().String())
+}
@@ -0,0 +1,150 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultConsoleTimeFormat   = "15:04:05.000"
+	defaultConsoleMessageWidth = 40
+)
+
+type ConsoleOptions struct {
+	// TimeFormat is a layout for time.Format applied to the local time, or
+	// to the time as returned by the clock if one is set so output of
+	// SetDeterministic does not depend on the time zone.
+	TimeFormat string
+
+	// MessageWidth pads the logger name and message so fields start in the
+	// same column. Longer messages are not truncated.
+	MessageWidth int
+}
+
+// SetConsoleOptions configures the ConsoleHandler. Zero values use the
+// defaults of a short local time and a message width of 40.
+func (l *Logger) SetConsoleOptions(opts ConsoleOptions) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.console = opts
+}
+
+func (m *msg) console(opts ConsoleOptions, t *Theme, local bool) string {
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = defaultConsoleTimeFormat
+	}
+
+	if opts.MessageWidth <= 0 {
+		opts.MessageWidth = defaultConsoleMessageWidth
+	}
+
+	var b strings.Builder
+
+	ts := m.Timestamp
+	if local {
+		ts = ts.Local()
+	}
+
+	b.WriteString(t.Timestamp.paint(ts.Format(opts.TimeFormat)))
+	b.WriteString(" ")
+	b.WriteString(t.level(m.Level).paint(fmt.Sprintf("%-3s", strings.ToUpper(m.Level.String()))))
+	b.WriteString(" ")
+
+	text := m.Msg
+	if m.Logger != "" {
+		text = m.Logger + ": " + m.Msg
+
+		b.WriteString(t.Logger.paint(m.Logger + ":"))
+		b.WriteString(" ")
+	}
+
+	b.WriteString(t.message(m.Level).paint(m.Msg))
+
+	flat := make(map[string]interface{})
+	flattenFields("", m.fields(), flat)
+
+	var (
+		inline    []string
+		multiline []string
+	)
+
+	for _, key := range sortedKeys(flat) {
+		value, isErr := consoleValue(key, flat[key])
+
+		keyStyle := t.Key
+		if isErr {
+			keyStyle = t.Error
+		}
+
+		if !strings.Contains(value, "\n") {
+			inline = append(inline, consoleField(key, flat[key], value, isErr, keyStyle, t))
+			continue
+		}
+
+		field := "\n    " + keyStyle.paint(key+":")
+		for _, line := range strings.Split(strings.TrimRight(value, "\n"), "\n") {
+			field += "\n      " + line
+		}
+
+		multiline = append(multiline, field)
+	}
+
+	if len(inline) > 0 {
+		if pad := opts.MessageWidth - len([]rune(text)); pad > 0 {
+			b.WriteString(strings.Repeat(" ", pad))
+		}
+
+		b.WriteString(" ")
+		b.WriteString(strings.Join(inline, " "))
+	}
+
+	b.WriteString(strings.Join(multiline, ""))
+
+	return b.String()
+}
+
+// consoleValue returns v formatted for the console and whether it is an error.
+// Strings holding JSON objects or arrays are indented.
+func consoleValue(key string, v interface{}) (string, bool) {
+	isErr := key == "error" || key == "err"
+
+	switch value := v.(type) {
+	case nil:
+		return "<nil>", isErr
+	case error:
+		return value.Error(), true
+	case string:
+		trimmed := strings.TrimSpace(value)
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			var buf bytes.Buffer
+			if json.Indent(&buf, []byte(trimmed), "", "  ") == nil && buf.Len() != len(trimmed) {
+				return buf.String(), isErr
+			}
+		}
+
+		return value, isErr
+	default:
+		return fmt.Sprintf("%v", value), isErr
+	}
+}
+
+func consoleField(key string, v interface{}, value string, isErr bool, keyStyle Style, t *Theme) string {
+	if _, ok := v.(string); ok || isErr {
+		if value == "" || strings.ContainsAny(value, " =\"\t") {
+			value = strconv.Quote(value)
+		}
+	}
+
+	valueStyle := t.value(v)
+	if isErr {
+		valueStyle = t.Error
+	}
+
+	return keyStyle.paint(key+"=") + valueStyle.paint(value)
+}
@@ -0,0 +1,126 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerConsoleHandler(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	prefix := ts.Format(defaultConsoleTimeFormat)
+
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(ConsoleHandler)
+	l.SetClock(FixedClock(ts))
+	l.SetColor(false)
+
+	tests := []struct {
+		name string
+		log  func()
+		want string
+	}{
+		{
+			name: "message only",
+			log:  func() { _, _ = l.Info("started") },
+			want: prefix + " INF started",
+		},
+		{
+			name: "aligned fields",
+			log:  func() { _, _ = l.Named("db").Warn("slow", "ms", 250, "query", "select 1", "ok", true) },
+			want: prefix + " WRN db: slow" + strings.Repeat(" ", 32) + ` ms=250 ok=true query="select 1"`,
+		},
+		{
+			name: "long message",
+			log:  func() { _, _ = l.Info(strings.Repeat("x", 45), "key", "value") },
+			want: prefix + " INF " + strings.Repeat("x", 45) + " key=value",
+		},
+		{
+			name: "groups and errors",
+			log: func() {
+				_, _ = l.WithGroup("req").Error("failed", "err", errors.New("broken pipe"), "id", 1)
+			},
+			want: prefix + ` ERR failed` + strings.Repeat(" ", 34) + ` req.err="broken pipe" req.id=1`,
+		},
+		{
+			name: "multi-line values",
+			log: func() {
+				_, _ = l.Error(
+					"panic",
+					"stack", "goroutine 1 [running]:\nmain.main()\n",
+					"body", `{"a":1,"b":[true]}`,
+					"status", 500,
+				)
+			},
+			want: prefix + " ERR panic" + strings.Repeat(" ", 35) + " status=500" +
+				"\n    body:" +
+				"\n      {" +
+				"\n        \"a\": 1," +
+				"\n        \"b\": [" +
+				"\n          true" +
+				"\n        ]" +
+				"\n      }" +
+				"\n    stack:" +
+				"\n      goroutine 1 [running]:" +
+				"\n      main.main()",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer buf.Reset()
+
+			tt.log()
+			require.Equal(t, tt.want+"\n", buf.String())
+		})
+	}
+}
+
+func TestLoggerSetConsoleOptions(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(ConsoleHandler)
+	l.SetClock(FixedClock(ts))
+	l.SetColor(false)
+	l.SetConsoleOptions(ConsoleOptions{TimeFormat: time.Kitchen, MessageWidth: 6})
+
+	_, _ = l.Info("test", "key", "value")
+	require.Equal(t, ts.Format(time.Kitchen)+" INF test   key=value\n", buf.String())
+}
+
+func TestConsoleColors(t *testing.T) {
+	m := &msg{
+		Level: LevelError,
+		Msg:   "failed",
+		Args:  map[string]interface{}{"error": "timeout", "n": 1, "name": "x"},
+	}
+
+	out := m.console(ConsoleOptions{MessageWidth: 1}, DarkTheme(), true)
+	require.Contains(t, out, string(colorRed)+"ERR"+string(colorReset))
+	require.Contains(t, out, string(colorRed)+"failed"+string(colorReset))
+	require.Contains(t, out, string(colorRed)+"error="+string(colorReset)+string(colorRed)+"timeout")
+	require.Contains(t, out, string(colorDim)+"n="+string(colorReset)+string(colorBlue)+"1")
+	require.Contains(t, out, string(colorDim)+"name="+string(colorReset)+string(colorGreen)+"x")
+
+	require.NotContains(t, m.console(ConsoleOptions{}, plainTheme, true), "\033[")
+
+	t.Run("error value under any key", func(t *testing.T) {
+		l := NewLogger()
+		buf := &bytes.Buffer{}
+		l.SetOut(buf)
+		l.SetHandler(ConsoleHandler)
+		l.SetConsoleOptions(ConsoleOptions{MessageWidth: 1})
+
+		_, _ = l.Info("x", "cause", errors.New("boom"))
+		require.Contains(t, buf.String(), string(colorRed)+"cause="+string(colorReset)+string(colorRed)+"boom")
+	})
+}
@@ -0,0 +1,21 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContext(t *testing.T) {
+	t.Run("no logger falls back to default", func(t *testing.T) {
+		require.Same(t, Default(), FromContext(context.Background()))
+	})
+
+	t.Run("with logger", func(t *testing.T) {
+		l := NewLogger()
+		ctx := NewContext(context.Background(), l)
+
+		require.Equal(t, l, FromContext(ctx))
+	})
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"sync/atomic"
+)
+
+var defaultLogger atomic.Pointer[Logger]
+
+func init() {
+	defaultLogger.Store(NewLogger())
+}
+
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+// SetDefault replaces the logger used by the package level functions.
+// Passing nil resets it to a new logger with default settings.
+func SetDefault(l *Logger) {
+	if l == nil {
+		l = NewLogger()
+	}
+
+	defaultLogger.Store(l)
+}
+
+func Debug(msg string, args ...interface{}) (int, error) {
+	return Default().log(LevelDebug, msg, args...)
+}
+
+func Info(msg string, args ...interface{}) (int, error) {
+	return Default().log(LevelInfo, msg, args...)
+}
+
+func Warn(msg string, args ...interface{}) (int, error) {
+	return Default().log(LevelWarn, msg, args...)
+}
+
+func Error(msg string, args ...interface{}) (int, error) {
+	return Default().log(LevelError, msg, args...)
+}
+
+func Fatal(msg string, args ...interface{}) {
+	Default().Fatal(msg, args...)
+}
+
+func Debugf(format string, args ...interface{}) (int, error) {
+	return Default().Logf(LevelDebug, format, args...)
+}
+
+func Infof(format string, args ...interface{}) (int, error) {
+	return Default().Logf(LevelInfo, format, args...)
+}
+
+func Warnf(format string, args ...interface{}) (int, error) {
+	return Default().Logf(LevelWarn, format, args...)
+}
+
+func Errorf(format string, args ...interface{}) (int, error) {
+	return Default().Logf(LevelError, format, args...)
+}
+
+func Fatalf(format string, args ...interface{}) {
+	Default().Fatalf(format, args...)
+}
+
+func With(args ...interface{}) *Logger {
+	return Default().With(args...)
+}
+
+func Named(name string) *Logger {
+	return Default().Named(name)
+}
@@ -0,0 +1,99 @@
+package log
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	original := Default()
+	defer SetDefault(original)
+
+	t.Run("default is set", func(t *testing.T) {
+		require.NotNil(t, Default())
+	})
+
+	t.Run("set nil resets", func(t *testing.T) {
+		SetDefault(nil)
+
+		require.NotNil(t, Default())
+		require.NotSame(t, original, Default())
+	})
+
+	t.Run("concurrent replace", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				l := NewLogger()
+				l.SetOut(&bytes.Buffer{})
+				SetDefault(l)
+			}()
+
+			go func() {
+				defer wg.Done()
+				_ = Default().GetLevel()
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+func TestPackageLevelFunctions(t *testing.T) {
+	original := Default()
+	defer SetDefault(original)
+
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetColor(false)
+	l.SetLevel(LevelDebug)
+	SetDefault(l)
+
+	cases := []struct {
+		Name     string
+		Log      func()
+		Expected string
+	}{
+		{Name: "debug", Log: func() { _, _ = Debug("test", "key", 1) }, Expected: `level=DBG msg="test" key=1`},
+		{Name: "info", Log: func() { _, _ = Info("test") }, Expected: `level=INF msg="test"`},
+		{Name: "warn", Log: func() { _, _ = Warn("test") }, Expected: `level=WRN msg="test"`},
+		{Name: "error", Log: func() { _, _ = Error("test") }, Expected: `level=ERR msg="test"`},
+		{Name: "debugf", Log: func() { _, _ = Debugf("n=%d", 1) }, Expected: `level=DBG msg="n=1"`},
+		{Name: "infof", Log: func() { _, _ = Infof("n=%d", 1) }, Expected: `level=INF msg="n=1"`},
+		{Name: "warnf", Log: func() { _, _ = Warnf("n=%d", 1) }, Expected: `level=WRN msg="n=1"`},
+		{Name: "errorf", Log: func() { _, _ = Errorf("n=%d", 1) }, Expected: `level=ERR msg="n=1"`},
+		{Name: "with", Log: func() { _, _ = With("key", 1).Info("test") }, Expected: `msg="test" key=1`},
+		{Name: "named", Log: func() { _, _ = Named("db").Info("test") }, Expected: `logger=db msg="test"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			defer buf.Reset()
+
+			c.Log()
+			require.Contains(t, buf.String(), c.Expected)
+		})
+	}
+
+	t.Run("fatal", func(t *testing.T) {
+		defer buf.Reset()
+
+		codes := []int{}
+		l.SetExitFunc(func(c int) { codes = append(codes, c) })
+
+		Fatal("test")
+		Fatalf("n=%d", 1)
+
+		require.Equal(t, []int{1, 1}, codes)
+		require.Contains(t, buf.String(), `level=FTL msg="n=1"`)
+	})
+}
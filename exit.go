@@ -0,0 +1,76 @@
+package log
+
+import "fmt"
+
+// FatalError is the value passed to panic by Fatal if SetPanicOnFatal is enabled.
+type FatalError struct {
+	Message string
+	Code    int
+}
+
+func (e *FatalError) Error() string {
+	return fmt.Sprintf("fatal: %s (exit code %d)", e.Message, e.Code)
+}
+
+func (l *Logger) SetExitCode(code int) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exitCode = code
+}
+
+// SetExitFunc replaces os.Exit for Fatal. Passing nil restores the default.
+func (l *Logger) SetExitFunc(fn func(code int)) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exitFunc = fn
+}
+
+// AddExitHook registers a function run by Fatal before exiting, e.g. to
+// flush buffered outputs. Hooks run in the order they were added.
+func (l *Logger) AddExitHook(hook func()) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exitHooks = append(r.exitHooks, hook)
+}
+
+func (l *Logger) SetPanicOnFatal(enabled bool) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.panicOnFatal = enabled
+}
+
+func (l *Logger) exit(msg string) {
+	l.mu.RLock()
+	code := l.exitCode
+	fn := l.exitFunc
+	hooks := make([]func(), len(l.exitHooks))
+	copy(hooks, l.exitHooks)
+	panicOnFatal := l.panicOnFatal
+	l.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	if panicOnFatal {
+		panic(&FatalError{Message: msg, Code: code})
+	}
+
+	if fn == nil {
+		fn = exit
+	}
+
+	fn(code)
+}
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerExit(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+
+	t.Run("default exit code", func(t *testing.T) {
+		code := -1
+		l.SetExitFunc(func(c int) { code = c })
+		defer l.SetExitFunc(nil)
+
+		l.Fatal("test")
+		require.Equal(t, 1, code)
+	})
+
+	t.Run("custom exit code and hooks", func(t *testing.T) {
+		var calls []string
+
+		l.SetExitCode(3)
+		defer l.SetExitCode(defaultExitCode)
+
+		l.AddExitHook(func() { calls = append(calls, "flush") })
+		l.AddExitHook(func() { calls = append(calls, "close") })
+		defer func() { l.exitHooks = nil }()
+
+		l.SetExitFunc(func(c int) { calls = append(calls, "exit") })
+		defer l.SetExitFunc(nil)
+
+		l.Named("db").Fatal("test")
+		require.Equal(t, []string{"flush", "close", "exit"}, calls)
+	})
+
+	t.Run("panic instead of exit", func(t *testing.T) {
+		l.SetPanicOnFatal(true)
+		defer l.SetPanicOnFatal(false)
+
+		l.SetExitFunc(func(c int) { t.Fatal("exit should not be called") })
+		defer l.SetExitFunc(nil)
+
+		defer func() {
+			err, ok := recover().(*FatalError)
+			require.True(t, ok)
+			require.Equal(t, "test", err.Message)
+			require.Equal(t, 1, err.Code)
+			require.Equal(t, "fatal: test (exit code 1)", err.Error())
+		}()
+
+		l.Fatal("test")
+	})
+}
@@ -0,0 +1,294 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type GELFCompression int
+
+const (
+	GELFCompressNone GELFCompression = iota
+	GELFCompressGzip
+	GELFCompressZlib
+)
+
+const (
+	gelfMaxChunks        = 128
+	gelfChunkHeaderSize  = 12
+	defaultGELFChunkSize = 1420
+)
+
+var ErrGELFTooLarge error = errors.New("gelf message exceeds maximum number of chunks")
+
+// GELFWriter sends records to Graylog as GELF 1.1 messages over "udp" or
+// "tcp". Configure the exported fields before passing it to SetOut.
+type GELFWriter struct {
+	Host string
+
+	// Compression and ChunkSize only apply to UDP. TCP messages are sent
+	// uncompressed and terminated by a null byte.
+	Compression GELFCompression
+	ChunkSize   int
+
+	network string
+	addr    string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func NewGELFWriter(network, addr string) (*GELFWriter, error) {
+	if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("unsupported gelf network: %q", network)
+	}
+
+	host, _ := os.Hostname()
+
+	w := &GELFWriter{
+		Host:      host,
+		ChunkSize: defaultGELFChunkSize,
+		network:   network,
+		addr:      addr,
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *GELFWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+
+	if _, err := w.WriteRecord(Record{Time: time.Now(), Level: LevelInfo, Message: msg}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *GELFWriter) WriteRecord(r Record) (int, error) {
+	b, err := w.encode(r)
+	if err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.network == "tcp" {
+		if err := w.sendTCP(append(b, 0)); err != nil {
+			return 0, err
+		}
+
+		return len(b), nil
+	}
+
+	b, err = w.compress(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := w.sendUDP(b); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+func (w *GELFWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
+
+func (w *GELFWriter) connect() error {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	conn, err := net.Dial(w.network, w.addr)
+	if err != nil {
+		return err
+	}
+
+	w.conn = conn
+
+	return nil
+}
+
+// sendTCP writes one frame and reconnects once if the write fails.
+func (w *GELFWriter) sendTCP(b []byte) error {
+	if w.conn != nil {
+		if _, err := w.conn.Write(b); err == nil {
+			return nil
+		}
+	}
+
+	if err := w.connect(); err != nil {
+		return err
+	}
+
+	_, err := w.conn.Write(b)
+
+	return err
+}
+
+func (w *GELFWriter) sendUDP(b []byte) error {
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return err
+		}
+	}
+
+	chunkSize := w.ChunkSize
+	if chunkSize <= gelfChunkHeaderSize {
+		chunkSize = defaultGELFChunkSize
+	}
+
+	if len(b) <= chunkSize {
+		_, err := w.conn.Write(b)
+		return err
+	}
+
+	dataSize := chunkSize - gelfChunkHeaderSize
+	count := (len(b) + dataSize - 1) / dataSize
+
+	if count > gelfMaxChunks {
+		return ErrGELFTooLarge
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		end := (i + 1) * dataSize
+		if end > len(b) {
+			end = len(b)
+		}
+
+		chunk := make([]byte, 0, gelfChunkHeaderSize+end-i*dataSize)
+		chunk = append(chunk, 0x1e, 0x0f)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, b[i*dataSize:end]...)
+
+		if _, err := w.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *GELFWriter) compress(b []byte) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		zw  io.WriteCloser
+	)
+
+	switch w.Compression {
+	case GELFCompressGzip:
+		zw = gzip.NewWriter(&buf)
+	case GELFCompressZlib:
+		zw = zlib.NewWriter(&buf)
+	default:
+		return b, nil
+	}
+
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (w *GELFWriter) encode(r Record) ([]byte, error) {
+	host := w.Host
+	if host == "" {
+		host = "unknown"
+	}
+
+	m := map[string]interface{}{
+		"version":       "1.1",
+		"host":          host,
+		"short_message": r.Message,
+		"timestamp":     float64(r.Time.UnixMilli()) / 1000,
+		"level":         syslogSeverity(r.Level),
+	}
+
+	if r.Logger != "" {
+		m["_logger"] = r.Logger
+	}
+
+	flat := make(map[string]interface{}, len(r.Fields))
+	flattenFields("", r.Fields, flat)
+
+	for key, value := range flat {
+		m[gelfFieldName(key)] = gelfValue(value)
+	}
+
+	return json.Marshal(m)
+}
+
+// gelfFieldName prefixes key with an underscore and replaces characters not
+// allowed in GELF additional field names. The reserved _id becomes _id_.
+func gelfFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	if name == "id" {
+		name = "id_"
+	}
+
+	return "_" + name
+}
+
+func gelfValue(v interface{}) interface{} {
+	switch v.(type) {
+	case string, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
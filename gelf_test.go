@@ -0,0 +1,244 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGELFEncode(t *testing.T) {
+	w := &GELFWriter{Host: "host"}
+
+	b, err := w.encode(Record{
+		Time:    time.UnixMilli(1715000000123),
+		Level:   LevelError,
+		Logger:  "db",
+		Message: "query failed",
+		Fields: map[string]interface{}{
+			"id":     7,
+			"table":  "users",
+			"ok":     false,
+			"http":   Fields{"status": 500},
+			"weird!": "x",
+		},
+	})
+	require.NoError(t, err)
+
+	expected := `{
+		"version": "1.1",
+		"host": "host",
+		"short_message": "query failed",
+		"timestamp": 1715000000.123,
+		"level": 3,
+		"_logger": "db",
+		"_id_": 7,
+		"_table": "users",
+		"_ok": "false",
+		"_http.status": 500,
+		"_weird_": "x"
+	}`
+	require.JSONEq(t, expected, string(b))
+}
+
+func readGELFDatagram(t *testing.T, pc net.PacketConn) []byte {
+	t.Helper()
+
+	buf := make([]byte, 65536)
+	require.NoError(t, pc.SetReadDeadline(time.Now().Add(time.Second)))
+
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+
+	return buf[:n]
+}
+
+func decodeGELF(t *testing.T, b []byte) map[string]interface{} {
+	t.Helper()
+
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &m))
+
+	return m
+}
+
+func TestGELFWriterUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	w, err := NewGELFWriter("udp", pc.LocalAddr().String())
+	require.NoError(t, err)
+	defer w.Close()
+
+	l := NewLogger()
+	l.SetOut(w)
+
+	t.Run("uncompressed", func(t *testing.T) {
+		_, err := l.Warn("test", "key", "value")
+		require.NoError(t, err)
+
+		m := decodeGELF(t, readGELFDatagram(t, pc))
+		require.Equal(t, "test", m["short_message"])
+		require.Equal(t, float64(4), m["level"])
+		require.Equal(t, "value", m["_key"])
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		w.Compression = GELFCompressGzip
+		defer func() { w.Compression = GELFCompressNone }()
+
+		_, err := l.Info("gzipped")
+		require.NoError(t, err)
+
+		zr, err := gzip.NewReader(bytes.NewReader(readGELFDatagram(t, pc)))
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		require.Equal(t, "gzipped", decodeGELF(t, b)["short_message"])
+	})
+
+	t.Run("zlib", func(t *testing.T) {
+		w.Compression = GELFCompressZlib
+		defer func() { w.Compression = GELFCompressNone }()
+
+		_, err := l.Info("zlibbed")
+		require.NoError(t, err)
+
+		zr, err := zlib.NewReader(bytes.NewReader(readGELFDatagram(t, pc)))
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		require.Equal(t, "zlibbed", decodeGELF(t, b)["short_message"])
+	})
+
+	t.Run("chunked", func(t *testing.T) {
+		w.ChunkSize = 100
+		defer func() { w.ChunkSize = defaultGELFChunkSize }()
+
+		message := strings.Repeat("x", 500)
+
+		_, err := l.Info(message)
+		require.NoError(t, err)
+
+		first := readGELFDatagram(t, pc)
+		require.Equal(t, []byte{0x1e, 0x0f}, first[:2])
+
+		count := int(first[11])
+		require.Greater(t, count, 1)
+
+		chunks := make([][]byte, count)
+		chunks[first[10]] = first[12:]
+
+		for i := 1; i < count; i++ {
+			chunk := readGELFDatagram(t, pc)
+			require.LessOrEqual(t, len(chunk), 100)
+			require.Equal(t, first[2:10], chunk[2:10])
+			chunks[chunk[10]] = chunk[12:]
+		}
+
+		require.Equal(t, message, decodeGELF(t, bytes.Join(chunks, nil))["short_message"])
+	})
+
+	t.Run("too many chunks", func(t *testing.T) {
+		w.ChunkSize = 20
+		defer func() { w.ChunkSize = defaultGELFChunkSize }()
+
+		_, err := l.Info(strings.Repeat("x", 2000))
+		require.ErrorIs(t, err, ErrGELFTooLarge)
+	})
+}
+
+func TestGELFWriterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	conns := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			conns <- conn
+		}
+	}()
+
+	w, err := NewGELFWriter("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer w.Close()
+
+	w.Compression = GELFCompressGzip
+
+	conn := <-conns
+
+	t.Run("null byte framing", func(t *testing.T) {
+		_, err := w.WriteRecord(Record{Time: time.Now(), Level: LevelInfo, Message: "first"})
+		require.NoError(t, err)
+		_, err = w.Write([]byte("second\n"))
+		require.NoError(t, err)
+
+		r := bufio.NewReader(conn)
+
+		for _, want := range []string{"first", "second"} {
+			frame, err := r.ReadBytes(0)
+			require.NoError(t, err)
+			require.Equal(t, want, decodeGELF(t, frame[:len(frame)-1])["short_message"])
+		}
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		require.NoError(t, conn.Close())
+
+		var next net.Conn
+
+		require.Eventually(t, func() bool {
+			_, _ = w.WriteRecord(Record{Time: time.Now(), Level: LevelInfo, Message: "again"})
+
+			select {
+			case next = <-conns:
+				return true
+			default:
+				return false
+			}
+		}, 2*time.Second, 10*time.Millisecond)
+
+		defer next.Close()
+
+		frame, err := bufio.NewReader(next).ReadBytes(0)
+		require.NoError(t, err)
+		require.Equal(t, "again", decodeGELF(t, frame[:len(frame)-1])["short_message"])
+	})
+
+	t.Run("failed send", func(t *testing.T) {
+		require.NoError(t, ln.Close())
+
+		require.Eventually(t, func() bool {
+			n, err := w.WriteRecord(Record{Time: time.Now(), Level: LevelInfo, Message: "lost"})
+			if err == nil {
+				return false
+			}
+
+			require.Zero(t, n)
+
+			return true
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestNewGELFWriterInvalidNetwork(t *testing.T) {
+	_, err := NewGELFWriter("unix", "/tmp/gelf.sock")
+	require.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package log
+
+// badGroupKey holds a value which was logged under the name of a group, as
+// the group replaces it.
+const badGroupKey = "!BADGROUP"
+
+// Fields is a set of fields rendered as a nested object by the JSON handler
+// and as dotted keys by the text handler.
+type Fields map[string]interface{}
+
+// Group returns the given key/value pairs as a value to be used as an inline
+// group, e.g. l.Info("request", "http", Group("method", "GET", "status", 200)).
+func Group(args ...interface{}) Fields {
+	return Fields(argsMapFromSlice(formatOddArgs(args...)...))
+}
+
+// WithGroup returns a logger which nests all fields added later, either via
+// With or as call arguments, under the given group name. A value already
+// bound under that name is kept in the group with the key "!BADGROUP".
+func (l *Logger) WithGroup(name string) *Logger {
+	if name == "" {
+		return l
+	}
+
+	groups := make([]string, len(l.groups), len(l.groups)+1)
+	copy(groups, l.groups)
+
+	c := l.child()
+	c.groups = append(groups, name)
+
+	return c
+}
+
+// withFieldsAt returns a copy of base with fields added below the given group
+// path. Nested groups along the path are copied, base itself is not modified.
+func withFieldsAt(
+	base map[string]interface{},
+	path []string,
+	fields map[string]interface{},
+) map[string]interface{} {
+	out := make(map[string]interface{}, len(base)+len(fields))
+	for k, v := range base {
+		out[k] = v
+	}
+
+	if len(path) == 0 {
+		for k, v := range fields {
+			out[k] = v
+		}
+
+		return out
+	}
+
+	sub, ok := out[path[0]].(Fields)
+	if v, exists := out[path[0]]; exists && !ok {
+		sub = Fields{badGroupKey: v}
+	}
+
+	out[path[0]] = Fields(withFieldsAt(sub, path[1:], fields))
+
+	return out
+}
+
+func flattenFields(prefix string, fields map[string]interface{}, out map[string]interface{}) {
+	for k, v := range fields {
+		if sub, ok := v.(Fields); ok {
+			flattenFields(prefix+k+".", sub, out)
+			continue
+		}
+
+		out[prefix+k] = v
+	}
+}
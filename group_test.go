@@ -0,0 +1,120 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	require.Equal(t, Fields{"method": "GET", "status": 200}, Group("method", "GET", "status", 200))
+	require.Equal(t, Fields{"no_key": "GET"}, Group("GET"))
+}
+
+func TestLoggerGroups(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetColor(false)
+
+	cases := []struct {
+		Name string
+		Log  func(l *Logger)
+		Text string
+		JSON string
+	}{
+		{
+			Name: "inline group",
+			Log: func(l *Logger) {
+				_, _ = l.Info("test", "http", Group("method", "GET", "status", 200))
+			},
+			Text: `msg="test" http.method="GET" http.status=200`,
+			JSON: `"msg":"test","http":{"method":"GET","status":200}`,
+		},
+		{
+			Name: "with group",
+			Log: func(l *Logger) {
+				_, _ = l.WithGroup("http").Info("test", "method", "GET")
+			},
+			Text: `msg="test" http.method="GET"`,
+			JSON: `"msg":"test","http":{"method":"GET"}`,
+		},
+		{
+			Name: "empty group is omitted",
+			Log: func(l *Logger) {
+				_, _ = l.WithGroup("http").Info("test")
+			},
+			Text: `msg="test"` + "\n",
+			JSON: `"msg":"test"}`,
+		},
+		{
+			Name: "composed with bound fields",
+			Log: func(l *Logger) {
+				_, _ = l.With("request_id", "abc").
+					WithGroup("http").
+					With("method", "GET").
+					WithGroup("response").
+					Info("test", "status", 200)
+			},
+			Text: `msg="test" http.method="GET" http.response.status=200 request_id="abc"`,
+			JSON: `"msg":"test","http":{"method":"GET","response":{"status":200}},"request_id":"abc"`,
+		},
+		{
+			Name: "named keeps groups",
+			Log: func(l *Logger) {
+				_, _ = l.WithGroup("http").Named("client").Info("test", "status", 200)
+			},
+			Text: `logger=client msg="test" http.status=200`,
+			JSON: `"logger":"client","msg":"test","http":{"status":200}`,
+		},
+		{
+			Name: "value under group name is kept",
+			Log: func(l *Logger) {
+				_, _ = l.With("http", 1).WithGroup("http").Info("test", "status", 200)
+			},
+			Text: `msg="test" http.!BADGROUP=1 http.status=200`,
+			JSON: `"msg":"test","http":{"!BADGROUP":1,"status":200}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name+" text", func(t *testing.T) {
+			defer buf.Reset()
+
+			l.SetHandler(TextHandler)
+			c.Log(l)
+			require.Contains(t, buf.String(), c.Text)
+		})
+
+		t.Run(c.Name+" json", func(t *testing.T) {
+			defer buf.Reset()
+
+			l.SetHandler(JSONHandler)
+			c.Log(l)
+			require.Contains(t, buf.String(), c.JSON)
+		})
+	}
+
+	t.Run("groups do not leak to parent", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetHandler(JSONHandler)
+
+		grouped := l.WithGroup("a")
+		_ = grouped.With("x", 1)
+		_ = grouped.WithGroup("b")
+		_, _ = grouped.Info("test", "y", 2)
+
+		require.Contains(t, buf.String(), `"msg":"test","a":{"y":2}}`)
+	})
+}
+
+func TestFormatArgsFlattensGroups(t *testing.T) {
+	args := map[string]interface{}{
+		"a":    1,
+		"http": Fields{"status": 200, "request": Fields{"method": "GET"}},
+	}
+
+	require.Equal(t, `a=1 http.request.method="GET" http.status=200`, formatArgs(args))
+}
@@ -5,6 +5,9 @@ type Handler int
 const (
 	TextHandler Handler = iota
 	JSONHandler
+
+	// ConsoleHandler writes human readable lines for development.
+	ConsoleHandler
 )
 
 func (h Handler) String() string {
@@ -13,6 +16,8 @@ func (h Handler) String() string {
 		return "text"
 	case JSONHandler:
 		return "json"
+	case ConsoleHandler:
+		return "console"
 	default:
 		return "unknown"
 	}
@@ -15,7 +15,11 @@ func TestHandlerString(t *testing.T) {
 		require.Equal(t, JSONHandler.String(), "json")
 	})
 
+	t.Run("console handler", func(t *testing.T) {
+		require.Equal(t, ConsoleHandler.String(), "console")
+	})
+
 	t.Run("unknown handler", func(t *testing.T) {
-		require.Equal(t, Handler(2).String(), "unknown")
+		require.Equal(t, Handler(3).String(), "unknown")
 	})
 }
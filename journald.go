@@ -0,0 +1,101 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrJournalUnsupported error = errors.New("journald is not supported on this platform")
+
+const defaultJournalSocket = "/run/systemd/journal/socket"
+
+// journalEntry encodes r in the native journal protocol. Values containing
+// newlines use the binary length prefixed form.
+func journalEntry(identifier string, r Record) []byte {
+	fields := map[string]string{
+		"MESSAGE":  r.Message,
+		"PRIORITY": fmt.Sprintf("%d", syslogSeverity(r.Level)),
+	}
+
+	if identifier != "" {
+		fields["SYSLOG_IDENTIFIER"] = identifier
+	}
+
+	if r.Logger != "" {
+		fields["LOGGER"] = r.Logger
+	}
+
+	flat := make(map[string]interface{}, len(r.Fields))
+	flattenFields("", r.Fields, flat)
+
+	for key, value := range flat {
+		name := journalFieldName(key)
+		if _, ok := fields[name]; ok {
+			name = "FIELD_" + name
+		}
+
+		fields[name] = fmt.Sprintf("%v", value)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+
+	for _, name := range names {
+		value := fields[name]
+
+		buf.WriteString(name)
+
+		if !strings.Contains(value, "\n") {
+			buf.WriteString("=")
+			buf.WriteString(value)
+			buf.WriteString("\n")
+
+			continue
+		}
+
+		buf.WriteString("\n")
+		_ = binary.Write(&buf, binary.LittleEndian, uint64(len(value)))
+		buf.WriteString(value)
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes()
+}
+
+// journalFieldName converts key to a valid journal field name: uppercase
+// letters, digits and underscores, not starting with an underscore or digit
+// and at most 64 characters long.
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	name = strings.TrimLeft(name, "_")
+
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "F_" + name
+	}
+
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return name
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// JournalWriter sends records to systemd-journald using its native protocol.
+type JournalWriter struct {
+	Identifier string
+
+	mu   sync.Mutex
+	conn *net.UnixConn
+}
+
+// NewJournalWriter connects to the journal socket at path, or to the default
+// socket if path is empty.
+func NewJournalWriter(path string) (*JournalWriter, error) {
+	if path == "" {
+		path = defaultJournalSocket
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &JournalWriter{
+		Identifier: filepath.Base(os.Args[0]),
+		conn:       conn,
+	}, nil
+}
+
+func (w *JournalWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+
+	if _, err := w.WriteRecord(Record{Time: time.Now(), Level: LevelInfo, Message: msg}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *JournalWriter) WriteRecord(r Record) (int, error) {
+	entry := journalEntry(w.Identifier, r)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.conn.Write(entry)
+	if err == nil {
+		return len(entry), nil
+	}
+
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return 0, err
+	}
+
+	if err := w.sendFile(entry); err != nil {
+		return 0, err
+	}
+
+	return len(entry), nil
+}
+
+func (w *JournalWriter) Close() error {
+	return w.conn.Close()
+}
+
+// sendFile passes entries too large for a datagram as an unlinked temporary
+// file descriptor, as done by sd_journal_send.
+func (w *JournalWriter) sendFile(entry []byte) error {
+	dir := "/dev/shm"
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		dir = os.TempDir()
+	}
+
+	f, err := os.CreateTemp(dir, "journal-")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+
+	if _, err := f.Write(entry); err != nil {
+		return err
+	}
+
+	raw, err := w.conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sendErr error
+
+	err = raw.Write(func(fd uintptr) bool {
+		sendErr = syscall.Sendmsg(int(fd), nil, syscall.UnixRights(int(f.Fd())), nil, 0)
+		return sendErr != syscall.EAGAIN
+	})
+	if err != nil {
+		return err
+	}
+
+	return sendErr
+}
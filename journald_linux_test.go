@@ -0,0 +1,99 @@
+package log
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func listenJournal(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "journal.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	return conn, path
+}
+
+func TestJournalWriter(t *testing.T) {
+	conn, path := listenJournal(t)
+
+	w, err := NewJournalWriter(path)
+	require.NoError(t, err)
+	defer w.Close()
+
+	w.Identifier = "app"
+
+	l := NewLogger()
+	l.SetOut(w)
+
+	t.Run("record", func(t *testing.T) {
+		_, err := l.Warn("test", "key", "value")
+		require.NoError(t, err)
+
+		buf := make([]byte, 4096)
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		require.Equal(t, "KEY=value\nMESSAGE=test\nPRIORITY=4\nSYSLOG_IDENTIFIER=app\n", string(buf[:n]))
+	})
+
+	t.Run("plain write", func(t *testing.T) {
+		_, err := w.Write([]byte("plain\n"))
+		require.NoError(t, err)
+
+		buf := make([]byte, 4096)
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		require.Contains(t, string(buf[:n]), "MESSAGE=plain\nPRIORITY=6\n")
+	})
+
+	t.Run("large entry via file descriptor", func(t *testing.T) {
+		value := strings.Repeat("x", 4<<20)
+
+		_, err := l.Info("large", "value", value)
+		require.NoError(t, err)
+
+		buf := make([]byte, 16)
+		oob := make([]byte, syscall.CmsgSpace(4))
+
+		n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+
+		fds, err := syscall.ParseUnixRights(&msgs[0])
+		require.NoError(t, err)
+		require.Len(t, fds, 1)
+
+		f := os.NewFile(uintptr(fds[0]), "journal")
+		defer f.Close()
+
+		_, err = f.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+
+		entry, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.Contains(t, string(entry), "MESSAGE=large\n")
+		require.Contains(t, string(entry), "VALUE="+value+"\n")
+	})
+}
+
+func TestNewJournalWriterMissingSocket(t *testing.T) {
+	_, err := NewJournalWriter(filepath.Join(t.TempDir(), "missing.sock"))
+	require.Error(t, err)
+}
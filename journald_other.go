@@ -0,0 +1,23 @@
+//go:build !linux
+
+package log
+
+type JournalWriter struct {
+	Identifier string
+}
+
+func NewJournalWriter(path string) (*JournalWriter, error) {
+	return nil, ErrJournalUnsupported
+}
+
+func (w *JournalWriter) Write(p []byte) (int, error) {
+	return 0, ErrJournalUnsupported
+}
+
+func (w *JournalWriter) WriteRecord(r Record) (int, error) {
+	return 0, ErrJournalUnsupported
+}
+
+func (w *JournalWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalEntry(t *testing.T) {
+	t.Run("simple values", func(t *testing.T) {
+		r := Record{
+			Level:   LevelError,
+			Logger:  "db",
+			Message: "query failed",
+			Fields: map[string]interface{}{
+				"table":    "users",
+				"http":     Fields{"status": 500},
+				"priority": "high",
+			},
+		}
+
+		expected := "FIELD_PRIORITY=high\n" +
+			"HTTP_STATUS=500\n" +
+			"LOGGER=db\n" +
+			"MESSAGE=query failed\n" +
+			"PRIORITY=3\n" +
+			"SYSLOG_IDENTIFIER=app\n" +
+			"TABLE=users\n"
+		require.Equal(t, expected, string(journalEntry("app", r)))
+	})
+
+	t.Run("multi line value", func(t *testing.T) {
+		r := Record{Level: LevelInfo, Message: "line 1\nline 2"}
+
+		size := make([]byte, 8)
+		binary.LittleEndian.PutUint64(size, 13)
+
+		expected := "MESSAGE\n" + string(size) + "line 1\nline 2\n" + "PRIORITY=6\n"
+		require.Equal(t, expected, string(journalEntry("", r)))
+	})
+}
+
+func TestJournalFieldName(t *testing.T) {
+	cases := []struct {
+		Key  string
+		Want string
+	}{
+		{Key: "key", Want: "KEY"},
+		{Key: "http.status", Want: "HTTP_STATUS"},
+		{Key: "_trusted", Want: "TRUSTED"},
+		{Key: "1st", Want: "F_1ST"},
+		{Key: "", Want: "F_"},
+		{Key: "ümlaut", Want: "MLAUT"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Key, func(t *testing.T) {
+			require.Equal(t, c.Want, journalFieldName(c.Key))
+		})
+	}
+}
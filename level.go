@@ -2,6 +2,7 @@ package log
 
 import (
 	"errors"
+	"strings"
 )
 
 var ErrInvalidLevel error = errors.New("invalid log level")
@@ -34,13 +35,22 @@ func (l Level) String() string {
 	}
 }
 
+// ParseLevel accepts the names "debug" to "fatal" and the abbreviations
+// written by the handlers like "inf" or "INF", ignoring case.
 func ParseLevel(level string) (Level, error) {
-	l, ok := levelStrings[level]
-	if !ok {
-		return LevelInvalid, ErrInvalidLevel
+	level = strings.ToLower(level)
+
+	if l, ok := levelStrings[level]; ok {
+		return l, nil
+	}
+
+	for _, l := range levelStrings {
+		if level == l.String() {
+			return l, nil
+		}
 	}
 
-	return l, nil
+	return LevelInvalid, ErrInvalidLevel
 }
 
 func MustParseLevel(level string) Level {
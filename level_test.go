@@ -17,6 +17,11 @@ func TestParseLevel(t *testing.T) {
 		{"warn", LevelWarn, 2},
 		{"error", LevelError, 3},
 		{"fatal", LevelFatal, 4},
+		{"WARN", LevelWarn, 2},
+		{"inf", LevelInfo, 1},
+		{"INF", LevelInfo, 1},
+		{"Dbg", LevelDebug, 0},
+		{"ftl", LevelFatal, 4},
 	}
 
 	for _, c := range cases {
@@ -32,6 +37,12 @@ func TestParseLevel(t *testing.T) {
 		require.Equal(t, ErrInvalidLevel, err)
 		require.Equal(t, LevelInvalid, l)
 	})
+
+	t.Run("invalid level name", func(t *testing.T) {
+		l, err := ParseLevel(LevelInvalid.String())
+		require.Equal(t, ErrInvalidLevel, err)
+		require.Equal(t, LevelInvalid, l)
+	})
 }
 
 func TestMustParseLevel(t *testing.T) {
@@ -0,0 +1,239 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LevelServer struct {
+	logger      *Logger
+	revertAfter time.Duration
+
+	mu       sync.Mutex
+	timer    *time.Timer
+	gen      int
+	revertAt time.Time
+	previous LevelSpec
+}
+
+type levelPayload struct {
+	Level       string            `json:"level"`
+	Components  map[string]string `json:"components,omitempty"`
+	RevertAfter string            `json:"revert_after,omitempty"`
+	RevertAt    *time.Time        `json:"revert_at,omitempty"`
+}
+
+func NewLevelServer(l *Logger) *LevelServer {
+	return &LevelServer{logger: l}
+}
+
+func (s *LevelServer) SetRevertAfter(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revertAfter = d
+}
+
+func (s *LevelServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s.writeLevel(w, r)
+	case http.MethodPut:
+		if err := s.putLevel(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.writeLevel(w, r)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *LevelServer) putLevel(r *http.Request) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+	if err != nil {
+		return err
+	}
+
+	revertAfter := r.URL.Query().Get("revert")
+
+	var spec LevelSpec
+
+	if isJSON(r.Header.Get("Content-Type")) {
+		var p levelPayload
+		if err := json.Unmarshal(body, &p); err != nil {
+			return fmt.Errorf("invalid json: %w", err)
+		}
+
+		spec, err = p.spec(s.logger)
+		if err != nil {
+			return err
+		}
+
+		if p.RevertAfter != "" {
+			revertAfter = p.RevertAfter
+		}
+	} else {
+		if strings.TrimSpace(string(body)) == "" {
+			return fmt.Errorf("%w: empty body", ErrInvalidLevelSpec)
+		}
+
+		spec, err = ParseLevelSpec(string(body))
+		if err != nil {
+			return err
+		}
+
+		// Like the JSON form, a spec naming no components keeps the
+		// current overrides instead of removing them.
+		if len(spec.Components) == 0 {
+			spec.Components = s.logger.ComponentLevels()
+		}
+	}
+
+	s.mu.Lock()
+	d := s.revertAfter
+	s.mu.Unlock()
+
+	if revertAfter != "" {
+		d, err = time.ParseDuration(revertAfter)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid revert duration: %q", revertAfter)
+		}
+	}
+
+	s.apply(spec, d)
+
+	return nil
+}
+
+func (s *LevelServer) apply(spec LevelSpec, revertAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.logger.GetLevelSpec()
+
+	// Keep the spec from before the first change so stacked
+	// changes during an incident all revert to the original.
+	if s.timer == nil {
+		s.previous = current
+	} else {
+		s.timer.Stop()
+		s.timer = nil
+		s.revertAt = time.Time{}
+	}
+
+	s.gen++
+
+	if revertAfter > 0 {
+		gen := s.gen
+		s.revertAt = time.Now().Add(revertAfter)
+		s.timer = time.AfterFunc(revertAfter, func() { s.revert(gen) })
+	}
+
+	s.logger.ApplyLevelSpec(spec)
+
+	// Written regardless of the new level so raising it does not hide
+	// the change itself.
+	_, _ = s.logger.write(
+		LevelInfo,
+		"log level changed",
+		"from", current.String(),
+		"to", s.logger.GetLevelSpec().String(),
+		"revert_after", revertAfter.String(),
+	)
+}
+
+func (s *LevelServer) revert(gen int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if gen != s.gen {
+		return
+	}
+
+	current := s.logger.GetLevelSpec()
+
+	s.logger.ApplyLevelSpec(s.previous)
+	s.timer = nil
+	s.revertAt = time.Time{}
+
+	_, _ = s.logger.write(
+		LevelInfo,
+		"log level reverted",
+		"from", current.String(),
+		"to", s.logger.GetLevelSpec().String(),
+	)
+}
+
+func (s *LevelServer) writeLevel(w http.ResponseWriter, r *http.Request) {
+	spec := s.logger.GetLevelSpec()
+
+	s.mu.Lock()
+	revertAt := s.revertAt
+	s.mu.Unlock()
+
+	if isJSON(r.Header.Get("Accept")) || r.URL.Query().Get("format") == "json" {
+		p := levelPayload{
+			Level:      levelName(spec.Level),
+			Components: make(map[string]string, len(spec.Components)),
+		}
+
+		for name, level := range spec.Components {
+			p.Components[name] = levelName(level)
+		}
+
+		if !revertAt.IsZero() {
+			p.RevertAt = &revertAt
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(p)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = fmt.Fprintln(w, spec.String())
+}
+
+func (p levelPayload) spec(l *Logger) (LevelSpec, error) {
+	spec := LevelSpec{
+		Level:      LevelInvalid,
+		Components: l.ComponentLevels(),
+	}
+
+	if p.Level != "" {
+		level, err := ParseLevel(p.Level)
+		if err != nil {
+			return LevelSpec{}, fmt.Errorf("%w: %q", err, p.Level)
+		}
+
+		spec.Level = level
+	}
+
+	if p.Components != nil {
+		spec.Components = make(map[string]Level, len(p.Components))
+
+		for name, levelStr := range p.Components {
+			level, err := ParseLevel(levelStr)
+			if err != nil {
+				return LevelSpec{}, fmt.Errorf("%w: %q", err, levelStr)
+			}
+
+			spec.Components[name] = level
+		}
+	}
+
+	return spec, nil
+}
+
+func isJSON(contentType string) bool {
+	return strings.Contains(contentType, "application/json")
+}
@@ -0,0 +1,192 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func doLevelRequest(
+	t *testing.T,
+	s *LevelServer,
+	method, target, contentType, body string,
+) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", contentType)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestLevelServerGet(t *testing.T) {
+	l := NewLogger()
+	l.SetOut(io.Discard)
+	l.SetComponentLevel("db", LevelDebug)
+
+	s := NewLevelServer(l)
+
+	t.Run("plain text", func(t *testing.T) {
+		rec := doLevelRequest(t, s, http.MethodGet, "/", "", "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "info,db=debug\n", rec.Body.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		rec := doLevelRequest(t, s, http.MethodGet, "/", "application/json", "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"level":"info","components":{"db":"debug"}}`, rec.Body.String())
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := doLevelRequest(t, s, http.MethodDelete, "/", "", "")
+
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestLevelServerPut(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+
+	s := NewLevelServer(l)
+
+	t.Run("plain text spec", func(t *testing.T) {
+		rec := doLevelRequest(t, s, http.MethodPut, "/", "text/plain", "warn,http=error")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, LevelWarn, l.GetLevel())
+		require.Equal(t, LevelError, l.Named("http").GetLevel())
+	})
+
+	t.Run("json keeps components when omitted", func(t *testing.T) {
+		rec := doLevelRequest(
+			t, s, http.MethodPut, "/", "application/json", `{"level":"debug"}`,
+		)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, LevelDebug, l.GetLevel())
+		require.Equal(t, LevelError, l.Named("http").GetLevel())
+		require.Contains(t, buf.String(), "log level changed")
+	})
+
+	t.Run("plain text keeps components when omitted", func(t *testing.T) {
+		rec := doLevelRequest(t, s, http.MethodPut, "/", "text/plain", "warn")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, LevelWarn, l.GetLevel())
+		require.Equal(t, map[string]Level{"http": LevelError}, l.ComponentLevels())
+	})
+
+	t.Run("change written above new level", func(t *testing.T) {
+		buf.Reset()
+
+		rec := doLevelRequest(t, s, http.MethodPut, "/", "text/plain", "error")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, LevelError, l.GetLevel())
+		require.Contains(t, buf.String(), "log level changed")
+	})
+
+	t.Run("json replaces components", func(t *testing.T) {
+		rec := doLevelRequest(
+			t, s, http.MethodPut, "/", "application/json", `{"level":"info","components":{"db":"warn"}}`,
+		)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, map[string]Level{"db": LevelWarn}, l.ComponentLevels())
+	})
+
+	cases := []struct {
+		Name        string
+		ContentType string
+		Body        string
+		Target      string
+	}{
+		{Name: "empty body", Body: ""},
+		{Name: "invalid spec", Body: "loud"},
+		{Name: "invalid json", ContentType: "application/json", Body: `{"level":`},
+		{Name: "invalid json level", ContentType: "application/json", Body: `{"level":"loud"}`},
+		{Name: "invalid revert", Body: "debug", Target: "/?revert=soon"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			target := c.Target
+			if target == "" {
+				target = "/"
+			}
+
+			rec := doLevelRequest(t, s, http.MethodPut, target, c.ContentType, c.Body)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			require.Equal(t, LevelInfo, l.GetLevel())
+		})
+	}
+}
+
+func TestLevelServerRevert(t *testing.T) {
+	l := NewLogger()
+	l.SetOut(io.Discard)
+
+	s := NewLevelServer(l)
+
+	t.Run("revert from query", func(t *testing.T) {
+		rec := doLevelRequest(t, s, http.MethodPut, "/?revert=50ms", "", "debug,db=warn")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, LevelDebug, l.GetLevel())
+
+		require.Eventually(t, func() bool {
+			return l.GetLevel() == LevelInfo && len(l.ComponentLevels()) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("stacked changes revert to original", func(t *testing.T) {
+		s.SetRevertAfter(time.Hour)
+		defer s.SetRevertAfter(0)
+
+		rec := doLevelRequest(t, s, http.MethodPut, "/", "application/json", `{"level":"debug"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var p levelPayload
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+		require.NotNil(t, p.RevertAt)
+
+		rec = doLevelRequest(
+			t, s, http.MethodPut, "/", "application/json", `{"level":"warn","revert_after":"50ms"}`,
+		)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, LevelWarn, l.GetLevel())
+
+		require.Eventually(t, func() bool {
+			return l.GetLevel() == LevelInfo
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("permanent change cancels revert", func(t *testing.T) {
+		rec := doLevelRequest(t, s, http.MethodPut, "/?revert=50ms", "", "debug")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rec = doLevelRequest(t, s, http.MethodPut, "/", "", "error")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		time.Sleep(100 * time.Millisecond)
+		require.Equal(t, LevelError, l.GetLevel())
+	})
+}
@@ -1,148 +1,300 @@
 package log
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Logger struct {
-	out     io.Writer
-	level   Level
-	handler Handler
+	mu         sync.RWMutex
+	outMu      sync.Mutex
+	out        io.Writer
+	level      Level
+	handler    Handler
+	components map[string]Level
+	clock      func() time.Time
+	noColor    bool
+	saved      *savedOutput
+	recorder   *flightRecorder
+	trace      TraceExtractor
+	jsonFormat JSONFormat
+	profile    *JSONProfile
+	console    ConsoleOptions
+	theme      *Theme
+
+	fallback         io.Writer
+	onWriteError     WriteErrorHandler
+	writeFailures    atomic.Uint64
+	fallbackWrites   atomic.Uint64
+	fallbackFailures atomic.Uint64
+
+	exitCode     int
+	exitFunc     func(code int)
+	exitHooks    []func()
+	panicOnFatal bool
+
+	// Loggers derived via Named or With share the configuration of their root.
+	parent *Logger
+	name   string
+	fields map[string]interface{}
+	groups []string
+	ctx    context.Context
 }
 
 func (l *Logger) SetOut(w io.Writer) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if w == nil {
-		l.out = defaultOut
+		r.out = defaultOut
 		return
 	}
 
-	l.out = w
+	r.out = w
 }
 
 func (l *Logger) SetLevel(level Level) {
+	if l.name != "" {
+		l.SetComponentLevel(l.name, level)
+		return
+	}
+
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if level < LevelInvalid || level > LevelFatal {
-		l.level = defaultLevel
+		r.level = defaultLevel
 		return
 	}
 
 	if level == LevelInvalid {
-		l.level = defaultLevel
+		r.level = defaultLevel
 		return
 	}
 
-	l.level = level
+	r.level = level
 }
 
 func (l *Logger) GetLevel() Level {
-	return l.level
+	return l.root().levelFor(l.name)
 }
 
 func (l *Logger) SetHandler(handler Handler) {
-	if handler != 0 && handler != 1 {
-		l.handler = defaultHandler
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if handler < TextHandler || handler > ConsoleHandler {
+		r.handler = defaultHandler
 		return
 	}
 
-	l.handler = handler
+	r.handler = handler
 }
 
 func NewLogger() *Logger {
 	return &Logger{
-		out:     defaultOut,
-		level:   defaultLevel,
-		handler: defaultHandler,
+		out:      defaultOut,
+		level:    defaultLevel,
+		handler:  defaultHandler,
+		exitCode: defaultExitCode,
 	}
 }
 
-func (l *Logger) Debug(msg string, args ...interface{}) (int, error) {
-	if !evalLevel(LevelDebug, l.level) {
-		return 0, nil
+func (l *Logger) With(args ...interface{}) *Logger {
+	if len(args) == 0 {
+		return l
 	}
 
-	args = formatOddArgs(args...)
+	c := l.child()
+	c.fields = withFieldsAt(l.fields, l.groups, argsMapFromSlice(formatOddArgs(args...)...))
 
-	out := msgFromParams(LevelDebug, msg, args...)
+	return c
+}
 
-	outStr, _ := l.msgToString(out)
+// child returns a logger sharing the root configuration and inheriting the
+// name, fields, groups and context of l.
+func (l *Logger) child() *Logger {
+	return &Logger{
+		parent: l.root(),
+		name:   l.name,
+		fields: l.fields,
+		groups: l.groups,
+		ctx:    l.ctx,
+	}
+}
 
-	return fmt.Fprintln(l.out, outStr)
+func (l *Logger) Debug(msg string, args ...interface{}) (int, error) {
+	return l.log(LevelDebug, msg, args...)
 }
 
 func (l *Logger) Info(msg string, args ...interface{}) (int, error) {
-	if !evalLevel(LevelInfo, l.level) {
-		return 0, nil
-	}
+	return l.log(LevelInfo, msg, args...)
+}
 
-	args = formatOddArgs(args...)
+func (l *Logger) Warn(msg string, args ...interface{}) (int, error) {
+	return l.log(LevelWarn, msg, args...)
+}
 
-	out := msgFromParams(LevelInfo, msg, args...)
+func (l *Logger) Error(msg string, args ...interface{}) (int, error) {
+	return l.log(LevelError, msg, args...)
+}
 
-	outStr, _ := l.msgToString(out)
+var exit func(code int) = os.Exit
 
-	return fmt.Fprintln(l.out, outStr)
+func (l *Logger) Fatal(msg string, args ...interface{}) {
+	_, _ = l.write(LevelFatal, msg, args...)
+	l.root().exit(msg)
 }
 
-func (l *Logger) Warn(msg string, args ...interface{}) (int, error) {
-	if !evalLevel(LevelWarn, l.level) {
+func (l *Logger) log(level Level, msg string, args ...interface{}) (int, error) {
+	if !l.Enabled(level) {
+		if r := l.root(); r.recording() {
+			r.record(l.newMsg(level, msg, args...))
+		}
+
 		return 0, nil
 	}
 
-	args = formatOddArgs(args...)
+	return l.write(level, msg, args...)
+}
 
-	out := msgFromParams(LevelWarn, msg, args...)
+func (l *Logger) write(level Level, msg string, args ...interface{}) (int, error) {
+	r := l.root()
+	out := l.newMsg(level, msg, args...)
 
-	outStr, _ := l.msgToString(out)
+	if r.recording() {
+		if level >= LevelError {
+			_, _ = r.FlushFlightRecorder()
+		} else {
+			r.record(out)
+		}
+	}
 
-	return fmt.Fprintln(l.out, outStr)
+	return r.emit(out)
 }
 
-func (l *Logger) Error(msg string, args ...interface{}) (int, error) {
-	if !evalLevel(LevelError, l.level) {
-		return 0, nil
-	}
+func (l *Logger) newMsg(level Level, msgStr string, args ...interface{}) *msg {
+	r := l.root()
 
 	args = formatOddArgs(args...)
 
-	out := msgFromParams(LevelError, msg, args...)
+	out := msgFromParams(level, msgStr, args...)
+	out.Logger = l.name
 
-	outStr, _ := l.msgToString(out)
+	switch {
+	case len(l.fields) == 0 && len(l.groups) == 0:
+	case len(out.Args) > 0:
+		out.Args = withFieldsAt(l.fields, l.groups, out.Args)
+	case len(l.fields) > 0:
+		out.Args = l.fields
+	}
+
+	out.Args = resolveFields(out.Args)
 
-	return fmt.Fprintln(l.out, outStr)
+	r.mu.RLock()
+	if r.clock != nil {
+		out.Timestamp = r.clock()
+	}
+	extract := r.trace
+	withSource := r.handler == JSONHandler && r.profile != nil && r.profile.SourceKey != ""
+	r.mu.RUnlock()
+
+	if withSource {
+		out.Source = callerSource()
+	}
+
+	if l.ctx != nil {
+		out.TraceID, out.SpanID = extractTrace(l.ctx, extract)
+	}
+
+	return out
 }
 
-var exit func(code int) = os.Exit
+func (l *Logger) emit(out *msg) (int, error) {
+	n, err := l.writeOut(out)
+	if err != nil {
+		return l.handleWriteError(out, err)
+	}
 
-func (l *Logger) Fatal(msg string, args ...interface{}) {
-	args = formatOddArgs(args...)
+	return n, nil
+}
 
-	out := msgFromParams(LevelFatal, msg, args...)
+func (l *Logger) writeOut(out *msg) (int, error) {
+	l.mu.RLock()
+	w := l.out
+	l.mu.RUnlock()
+
+	return l.writeTo(w, out)
+}
 
+// writeTo writes out to w, which is the output or the fallback output.
+func (l *Logger) writeTo(w io.Writer, out *msg) (int, error) {
+	if rw, ok := w.(RecordWriter); ok {
+		l.outMu.Lock()
+		defer l.outMu.Unlock()
+
+		return rw.WriteRecord(out.record())
+	}
+
+	l.mu.RLock()
 	outStr, _ := l.msgToString(out)
+	l.mu.RUnlock()
+
+	l.outMu.Lock()
+	defer l.outMu.Unlock()
 
-	_, _ = fmt.Fprintln(l.out, outStr)
-	exit(1)
+	return fmt.Fprintln(w, outStr)
 }
 
 func (l *Logger) msgToString(msg *msg) (string, error) {
 	switch l.handler {
 	case TextHandler:
-		return msg.String(), nil
+		return msg.text(l.themeFor(TextHandler)), nil
 	case JSONHandler:
+		if l.jsonFormat == JSONFormatOTel {
+			b, _ := msg.marshalOTel()
+			return string(b), nil
+		}
+
+		if l.profile != nil {
+			b, _ := msg.marshalProfile(l.profile)
+			return string(b), nil
+		}
+
 		b, _ := msg.Marshal()
 		return string(b), nil
+	case ConsoleHandler:
+		return msg.console(l.console, l.themeFor(ConsoleHandler), l.clock == nil), nil
 	default:
 		return "", errors.New("invalid handler")
 	}
 }
 
+func (l *Logger) root() *Logger {
+	if l.parent != nil {
+		return l.parent
+	}
+
+	return l
+}
+
 var (
-	defaultOut     io.Writer = os.Stderr
-	defaultLevel   Level     = LevelInfo
-	defaultHandler Handler   = TextHandler
+	defaultOut      io.Writer = os.Stderr
+	defaultLevel    Level     = LevelInfo
+	defaultHandler  Handler   = TextHandler
+	defaultExitCode int       = 1
 )
 
 func formatOddArgs(args ...interface{}) []interface{} {
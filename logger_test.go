@@ -1,6 +1,7 @@
 package log
 
 import (
+	"bytes"
 	"os"
 	"testing"
 	"time"
@@ -75,7 +76,7 @@ func TestLoggerSetHandler(t *testing.T) {
 	})
 
 	t.Run("invalid handler, use default", func(t *testing.T) {
-		l.SetHandler(2)
+		l.SetHandler(3)
 		require.Equal(t, l.handler, defaultHandler)
 	})
 }
@@ -241,3 +242,40 @@ func TestArgsMapFromSlice(t *testing.T) {
 		require.Equal(t, argsMapFromSlice(sampleArgs...), sampleMap)
 	})
 }
+
+func TestLoggerWith(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(JSONHandler)
+
+	t.Run("no args returns same logger", func(t *testing.T) {
+		require.Equal(t, l, l.With())
+	})
+
+	t.Run("bound fields", func(t *testing.T) {
+		defer buf.Reset()
+
+		child := l.With("key", "value", "count", 1)
+		_, _ = child.Info("test", "count", 2)
+
+		require.Contains(t, buf.String(), `"msg":"test","count":2,"key":"value"`)
+	})
+
+	t.Run("bound fields do not leak to parent", func(t *testing.T) {
+		defer buf.Reset()
+
+		_ = l.With("key", "value")
+		_, _ = l.Info("test")
+
+		require.NotContains(t, buf.String(), "key")
+	})
+
+	t.Run("named keeps fields", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.With("key", "value").Named("db").Info("test")
+
+		require.Contains(t, buf.String(), `"logger":"db","msg":"test","key":"value"`)
+	})
+}
@@ -0,0 +1,40 @@
+package logtest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// RequireGolden compares got with testdata/<name>.golden and fails the test
+// on mismatch. Running the tests with -update rewrites the golden file.
+func RequireGolden(tb testing.TB, name string, got []byte) {
+	tb.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("create golden dir: %v", err)
+		}
+
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			tb.Fatalf("update golden file: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+
+	if !bytes.Equal(want, got) {
+		tb.Fatalf("output does not match %s (run with -update to accept)\nwant:\n%s\ngot:\n%s", path, want, got)
+	}
+}
@@ -0,0 +1,43 @@
+package logtest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/devusSs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireGolden(t *testing.T) {
+	for _, handler := range []log.Handler{log.TextHandler, log.JSONHandler, log.ConsoleHandler} {
+		t.Run(handler.String(), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			l := log.NewLogger()
+			l.SetOut(buf)
+			l.SetHandler(handler)
+			l.SetDeterministic(true)
+
+			_, _ = l.Info("starting", "port", 8080, "host", "localhost")
+			_, _ = l.Named("db").Warn("slow query", "ms", 1200, "table", "users")
+
+			RequireGolden(t, "deterministic_"+handler.String(), buf.Bytes())
+		})
+	}
+
+	if *update {
+		return
+	}
+
+	t.Run("mismatch", func(t *testing.T) {
+		tb := &fakeTB{}
+		RequireGolden(tb, "deterministic_text", []byte("other"))
+		require.True(t, tb.failed)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		tb := &fakeTB{}
+		RequireGolden(tb, "does_not_exist", nil)
+		require.True(t, tb.failed)
+	})
+}
@@ -0,0 +1,166 @@
+package logtest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/devusSs/log"
+)
+
+// Recorder captures records logged through a *log.Logger for assertions.
+type Recorder struct {
+	mu      sync.Mutex
+	records []log.Record
+	w       io.Writer
+}
+
+// New returns a logger at LevelDebug recording into the returned Recorder.
+// Records are also written to tb.Log so they show up for failed tests.
+func New(tb testing.TB) (*log.Logger, *Recorder) {
+	r := &Recorder{}
+	if tb != nil {
+		r.w = NewWriter(tb)
+	}
+
+	l := log.NewLogger()
+	l.SetLevel(log.LevelDebug)
+	l.SetOut(r)
+
+	return l, r
+}
+
+func (r *Recorder) WriteRecord(rec log.Record) (int, error) {
+	r.mu.Lock()
+	r.records = append(r.records, rec)
+	r.mu.Unlock()
+
+	line := rec.String()
+
+	if r.w != nil {
+		_, _ = io.WriteString(r.w, line)
+	}
+
+	return len(line), nil
+}
+
+// Write forwards formatted output to the test log, if any. Records logged
+// through a logger using the Recorder as output arrive via WriteRecord.
+func (r *Recorder) Write(p []byte) (int, error) {
+	if r.w == nil {
+		return len(p), nil
+	}
+
+	return r.w.Write(p)
+}
+
+func (r *Recorder) Records() []log.Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records := make([]log.Record, len(r.records))
+	copy(records, r.records)
+
+	return records
+}
+
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = nil
+}
+
+// Find returns all records with the given level and message whose fields
+// contain the given key/value pairs.
+func (r *Recorder) Find(level log.Level, msg string, args ...interface{}) []log.Record {
+	var found []log.Record
+
+	for _, rec := range r.Records() {
+		if rec.Level == level && rec.Message == msg && hasFields(rec, args...) {
+			found = append(found, rec)
+		}
+	}
+
+	return found
+}
+
+func (r *Recorder) RequireLogged(tb testing.TB, level log.Level, msg string, args ...interface{}) {
+	tb.Helper()
+
+	if len(r.Find(level, msg, args...)) == 0 {
+		tb.Fatalf("no record matching %s\nrecorded:\n%s", describe(level, msg, args...), r.dump())
+	}
+}
+
+func (r *Recorder) RequireNotLogged(tb testing.TB, level log.Level, msg string, args ...interface{}) {
+	tb.Helper()
+
+	if len(r.Find(level, msg, args...)) != 0 {
+		tb.Fatalf("unexpected record matching %s\nrecorded:\n%s", describe(level, msg, args...), r.dump())
+	}
+}
+
+func (r *Recorder) dump() string {
+	var b strings.Builder
+
+	for _, rec := range r.Records() {
+		b.WriteString("\t")
+		b.WriteString(rec.String())
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func hasFields(rec log.Record, args ...interface{}) bool {
+	for i := 0; i+1 < len(args); i += 2 {
+		got, ok := rec.Fields[fmt.Sprintf("%v", args[i])]
+		if !ok || !reflect.DeepEqual(got, args[i+1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func describe(level log.Level, msg string, args ...interface{}) string {
+	return fmt.Sprintf("level=%s msg=%q fields=%v", strings.ToUpper(level.String()), msg, args)
+}
+
+type tbWriter struct {
+	tb testing.TB
+}
+
+// NewWriter returns a writer passing each line to tb.Log with color codes
+// stripped, so log output is only shown for failed or verbose tests.
+func NewWriter(tb testing.TB) io.Writer {
+	return &tbWriter{tb: tb}
+}
+
+var ansiCodes = regexp.MustCompile("\033\\[[0-9;]*m")
+
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.tb.Helper()
+
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		w.tb.Log(string(ansiCodes.ReplaceAll(line, nil)))
+	}
+
+	return len(p), nil
+}
+
+// NewLogger returns a logger at LevelDebug writing to tb.Log.
+func NewLogger(tb testing.TB) *log.Logger {
+	l := log.NewLogger()
+	l.SetLevel(log.LevelDebug)
+	l.SetColor(false)
+	l.SetOut(NewWriter(tb))
+
+	return l
+}
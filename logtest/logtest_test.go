@@ -0,0 +1,88 @@
+package logtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/devusSs/log"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTB struct {
+	testing.TB
+	logs   []string
+	failed bool
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Log(args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprint(args...))
+}
+
+func (f *fakeTB) Fatalf(format string, args ...interface{}) {
+	f.failed = true
+}
+
+func TestRecorder(t *testing.T) {
+	l, rec := New(t)
+
+	_, _ = l.Debug("starting", "port", 8080)
+	_, _ = l.Named("db").Error("query failed", "table", "users", "attempt", 2)
+
+	t.Run("records", func(t *testing.T) {
+		records := rec.Records()
+		require.Len(t, records, 2)
+
+		require.Equal(t, log.LevelDebug, records[0].Level)
+		require.Equal(t, "starting", records[0].Message)
+		require.Equal(t, map[string]interface{}{"port": 8080}, records[0].Fields)
+
+		require.Equal(t, "db", records[1].Logger)
+	})
+
+	t.Run("require logged", func(t *testing.T) {
+		rec.RequireLogged(t, log.LevelError, "query failed")
+		rec.RequireLogged(t, log.LevelError, "query failed", "table", "users", "attempt", 2)
+		rec.RequireNotLogged(t, log.LevelError, "query failed", "table", "orders")
+		rec.RequireNotLogged(t, log.LevelInfo, "starting")
+	})
+
+	t.Run("require logged fails", func(t *testing.T) {
+		tb := &fakeTB{}
+		rec.RequireLogged(tb, log.LevelWarn, "missing")
+		require.True(t, tb.failed)
+
+		tb = &fakeTB{}
+		rec.RequireNotLogged(tb, log.LevelDebug, "starting", "port", 8080)
+		require.True(t, tb.failed)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		rec.Reset()
+		require.Empty(t, rec.Records())
+	})
+}
+
+func TestRecorderLogsToTB(t *testing.T) {
+	tb := &fakeTB{}
+	l, _ := New(tb)
+
+	_, _ = l.Info("hello", "key", "value")
+
+	require.Len(t, tb.logs, 1)
+	require.Contains(t, tb.logs[0], `level=INF msg="hello" key="value"`)
+}
+
+func TestNewLogger(t *testing.T) {
+	tb := &fakeTB{}
+	l := NewLogger(tb)
+
+	_, _ = l.Debug("first")
+	_, _ = l.Warn("second")
+
+	require.Len(t, tb.logs, 2)
+	require.Contains(t, tb.logs[0], `level=DBG msg="first"`)
+	require.Contains(t, tb.logs[1], `level=WRN msg="second"`)
+	require.NotContains(t, tb.logs[1], "\033[")
+}
@@ -0,0 +1,198 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(JSONHandler)
+
+	handler := Middleware(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NotNil(t, FromContext(r.Context()))
+		_, _ = FromContext(r.Context()).Info("handling")
+
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte("hello"))
+		}
+	}))
+
+	cases := []struct {
+		Name   string
+		Path   string
+		Status int
+		Level  string
+	}{
+		{Name: "ok", Path: "/", Status: http.StatusOK, Level: "inf"},
+		{Name: "not found", Path: "/missing", Status: http.StatusNotFound, Level: "wrn"},
+		{Name: "server error", Path: "/broken", Status: http.StatusInternalServerError, Level: "err"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			defer buf.Reset()
+
+			req := httptest.NewRequest(http.MethodGet, c.Path, nil)
+			req.Header.Set("User-Agent", "test-agent")
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, c.Status, rec.Code)
+
+			id := rec.Header().Get(RequestIDHeader)
+			require.Len(t, id, 32)
+
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+			require.Len(t, lines, 2)
+			require.Contains(t, string(lines[0]), fmt.Sprintf(`"request_id":"%s"`, id))
+
+			access := string(lines[1])
+			require.Contains(t, access, fmt.Sprintf(`"level":"%s","msg":"request"`, c.Level))
+			require.Contains(t, access, `"method":"GET"`)
+			require.Contains(t, access, fmt.Sprintf(`"path":"%s"`, c.Path))
+			require.Contains(t, access, fmt.Sprintf(`"status":%d`, c.Status))
+			require.Contains(t, access, fmt.Sprintf(`"bytes":%d`, rec.Body.Len()))
+			require.Contains(t, access, `"user_agent":"test-agent"`)
+			require.Contains(t, access, `"remote_addr":"192.0.2.1:1234"`)
+			require.Contains(t, access, fmt.Sprintf(`"request_id":"%s"`, id))
+		})
+	}
+
+	t.Run("request id from header", func(t *testing.T) {
+		defer buf.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "abc")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, "abc", rec.Header().Get(RequestIDHeader))
+		require.Contains(t, buf.String(), `"request_id":"abc"`)
+	})
+
+	invalidIDs := map[string]string{
+		"too long":      strings.Repeat("a", 129),
+		"control chars": "abc\ninjected=1",
+		"spaces":        "abc def",
+		"non ascii":     "abc\u00e9",
+	}
+
+	for name, invalid := range invalidIDs {
+		t.Run("request id from header "+name, func(t *testing.T) {
+			defer buf.Reset()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(RequestIDHeader, invalid)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(RequestIDHeader)
+			require.Len(t, id, 32)
+			require.NotContains(t, buf.String(), invalid)
+			require.Contains(t, buf.String(), fmt.Sprintf(`"request_id":"%s"`, id))
+		})
+	}
+
+	t.Run("trace from request context", func(t *testing.T) {
+		defer buf.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(ContextWithTrace(req.Context(), "trace", "span"))
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+
+		for _, line := range lines {
+			require.Contains(t, string(line), `"span_id":"span"`)
+			require.Contains(t, string(line), `"trace_id":"trace"`)
+		}
+	})
+}
+
+func TestMiddlewareFlusher(t *testing.T) {
+	l := NewLogger()
+	l.SetOut(&bytes.Buffer{})
+
+	handler := Middleware(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, ok := w.(http.Flusher)
+		require.True(t, ok)
+		f.Flush()
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.True(t, rec.Flushed)
+}
+
+func TestMiddlewareHijacker(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(JSONHandler)
+
+	t.Run("not supported", func(t *testing.T) {
+		handler := Middleware(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _, err := w.(http.Hijacker).Hijack()
+			require.Error(t, err)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	t.Run("passthrough", func(t *testing.T) {
+		lines := make(chanWriter, 1)
+		l.SetOut(lines)
+
+		srv := httptest.NewServer(Middleware(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, rw, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			defer conn.Close()
+
+			_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\n")
+			_ = rw.Flush()
+		})))
+		defer srv.Close()
+
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\n\r\n"))
+		require.NoError(t, err)
+
+		status, err := bufio.NewReader(conn).ReadString('\n')
+		require.NoError(t, err)
+		require.Contains(t, status, "101")
+
+		require.Contains(t, <-lines, `"status":101`)
+	})
+}
+
+type chanWriter chan string
+
+func (w chanWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
@@ -13,24 +13,45 @@ import (
 type msg struct {
 	Timestamp time.Time              `json:"timestamp"`
 	Level     Level                  `json:"level"`
+	Logger    string                 `json:"logger,omitempty"`
 	Msg       string                 `json:"msg"`
 	Args      map[string]interface{} `json:"-"`
+	TraceID   string                 `json:"-"`
+	SpanID    string                 `json:"-"`
+	Source    *Source                `json:"-"`
 }
 
 func (m *msg) String() string {
-	ts := formatTimestampRFC3339(m.Timestamp)
-	l := formatLevel(m.Level)
+	if noColor {
+		return m.text(plainTheme)
+	}
+
+	return m.text(textTheme)
+}
+
+// text formats m as logfmt colored by t.
+func (m *msg) text(t *Theme) string {
+	ts := t.Timestamp.paint(formatTimestampRFC3339(m.Timestamp))
+
+	l := t.level(m.Level).paint(strings.ToUpper(m.Level.String()))
 
-	if m.Args == nil || len(m.Args) == 0 {
-		return fmt.Sprintf(`timestamp=%s level=%s msg="%s"`, ts, l, m.Msg)
+	if m.Logger != "" {
+		l = fmt.Sprintf("%s logger=%s", l, t.Logger.paint(m.Logger))
+	}
+
+	message := t.message(m.Level).paint(m.Msg)
+
+	args := m.fields()
+	if len(args) == 0 {
+		return fmt.Sprintf(`timestamp=%s level=%s msg="%s"`, ts, l, message)
 	}
 
 	return fmt.Sprintf(
 		`timestamp=%s level=%s msg="%s" %v`,
-		formatTimestampRFC3339(m.Timestamp),
-		formatLevel(m.Level),
-		m.Msg,
-		formatArgs(m.Args),
+		ts,
+		l,
+		message,
+		formatArgsTheme(args, t),
 	)
 }
 
@@ -48,15 +69,24 @@ func (m *msg) Marshal() ([]byte, error) {
 	buf.Write(jsonValue)
 	buf.WriteString(",")
 
+	if m.Logger != "" {
+		buf.WriteString(`"logger":`)
+		jsonValue, _ = json.Marshal(m.Logger)
+		buf.Write(jsonValue)
+		buf.WriteString(",")
+	}
+
 	buf.WriteString(`"msg":`)
 	jsonValue, _ = json.Marshal(m.Msg)
 	buf.Write(jsonValue)
 
-	for key, value := range m.Args {
-		buf.WriteString(`,"`)
-		buf.WriteString(key)
-		buf.WriteString(`":`)
-		jsonValue, _ = json.Marshal(value)
+	args := m.fields()
+	for _, key := range sortedKeys(args) {
+		buf.WriteString(",")
+		jsonValue, _ = json.Marshal(key)
+		buf.Write(jsonValue)
+		buf.WriteString(":")
+		jsonValue, _ = json.Marshal(jsonArg(args[key]))
 		buf.Write(jsonValue)
 	}
 	buf.WriteString("}")
@@ -64,54 +94,60 @@ func (m *msg) Marshal() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func formatTimestampRFC3339(t time.Time) string {
-	return t.Format(time.RFC3339)
-}
+// fields returns the arguments of m including the trace_id and span_id of
+// the context it was logged with.
+func (m *msg) fields() map[string]interface{} {
+	if m.TraceID == "" && m.SpanID == "" {
+		return m.Args
+	}
 
-func formatLevel(l Level) string {
-	level := strings.ToUpper(l.String())
+	args := make(map[string]interface{}, len(m.Args)+2)
+	for k, v := range m.Args {
+		args[k] = v
+	}
 
-	if noColor {
-		return strings.ToUpper(level)
+	if m.TraceID != "" {
+		args[TraceIDKey] = m.TraceID
 	}
 
-	switch l {
-	case LevelDebug:
-		return colorString(level, colorWhite)
-	case LevelInfo:
-		return colorString(level, colorCyan)
-	case LevelWarn:
-		return colorString(level, colorYellow)
-	case LevelError:
-		return colorString(level, colorRed)
-	case LevelFatal:
-		return colorString(level, colorRed)
-	default:
-		return colorString(level, colorWhite)
+	if m.SpanID != "" {
+		args[SpanIDKey] = m.SpanID
 	}
+
+	return args
+}
+
+func formatTimestampRFC3339(t time.Time) string {
+	return t.Format(time.RFC3339)
 }
 
 func formatArgs(args map[string]interface{}) string {
+	return formatArgsTheme(args, plainTheme)
+}
+
+func formatArgsTheme(args map[string]interface{}, t *Theme) string {
 	if len(args) == 0 {
 		return ""
 	}
 
-	keys := make([]string, 0, len(args))
-	for k := range args {
-		keys = append(keys, k)
-	}
+	flat := make(map[string]interface{}, len(args))
+	flattenFields("", args, flat)
 
-	sort.Strings(keys)
+	keys := sortedKeys(flat)
 
 	buf := ""
 
 	for i, key := range keys {
-		value := args[key]
+		value := flat[key]
 
-		kv := fmt.Sprintf("%s=%v ", key, checkStringType(value))
+		kv := fmt.Sprintf(
+			"%s=%s ",
+			t.Key.paint(key),
+			t.field(key, value).paint(fmt.Sprintf("%v", checkStringType(value))),
+		)
 
 		if len(keys)-1 == i {
-			kv = fmt.Sprintf("%s=%v", key, checkStringType(value))
+			kv = strings.TrimSuffix(kv, " ")
 		}
 
 		buf += kv
@@ -120,6 +156,17 @@ func formatArgs(args map[string]interface{}) string {
 	return buf
 }
 
+func sortedKeys(args map[string]interface{}) []string {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
 func checkStringType(v interface{}) interface{} {
 	if v == nil {
 		return ""
@@ -129,5 +176,9 @@ func checkStringType(v interface{}) interface{} {
 		return fmt.Sprintf(`"%v"`, v)
 	}
 
+	if err, ok := v.(error); ok && !isNilPointer(v) {
+		return fmt.Sprintf(`"%v"`, err.Error())
+	}
+
 	return v
 }
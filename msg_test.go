@@ -1,6 +1,7 @@
 package log
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -85,7 +86,6 @@ func TestMsgMarshal(t *testing.T) {
 		require.Equal(t, string(b), expected)
 	})
 
-	// TODO: this errors because key/value pairs get changed
 	t.Run("msg with args", func(t *testing.T) {
 		msg := &msg{
 			Timestamp: time.Time{},
@@ -100,6 +100,20 @@ func TestMsgMarshal(t *testing.T) {
 		expected := `{"timestamp":"0001-01-01T00:00:00Z","level":"inf","msg":"test","key":"value","key2":42}`
 		require.Equal(t, string(b), expected)
 	})
+	t.Run("msg with error args", func(t *testing.T) {
+		msg := &msg{
+			Timestamp: time.Time{},
+			Level:     LevelInfo,
+			Msg:       "test",
+			Args:      map[string]interface{}{"cause": errors.New("boom"), "req": Fields{"err": errors.New("eof")}},
+		}
+
+		b, err := msg.Marshal()
+		require.NoError(t, err)
+
+		expected := `{"timestamp":"0001-01-01T00:00:00Z","level":"inf","msg":"test","cause":"boom","req":{"err":"eof"}}`
+		require.Equal(t, string(b), expected)
+	})
 }
 
 func TestFormatTimestampRFC3339(t *testing.T) {
@@ -182,6 +196,7 @@ func TestCheckStringType(t *testing.T) {
 		{Name: "integer arg", Arg: 42, Expected: 42},
 		{Name: "string arg", Arg: "42", Expected: `"42"`},
 		{Name: "map arg", Arg: sampleMap, Expected: sampleMap},
+		{Name: "error arg", Arg: errors.New("boom"), Expected: `"boom"`},
 	}
 
 	for _, c := range cases {
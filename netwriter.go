@@ -0,0 +1,498 @@
+package log
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrNetWriterClosed  error = errors.New("network writer is closed")
+	ErrNetWriterDropped error = errors.New("network writer buffer is full, record dropped")
+)
+
+type NetWriterOptions struct {
+	// TLSConfig enables TLS if set.
+	TLSConfig *tls.Config
+
+	// BufferSize is the number of writes kept in memory while disconnected.
+	BufferSize int
+
+	// SpoolPath is a file receiving writes once the memory buffer is full.
+	// Its content is replayed in order after the memory buffer, including
+	// content left over from a previous process. Writes are dropped if empty.
+	SpoolPath string
+
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// CloseTimeout limits how long Close waits for buffered writes to be sent.
+	CloseTimeout time.Duration
+}
+
+type NetWriterStats struct {
+	Delivered uint64
+	Dropped   uint64
+	Spooled   uint64
+
+	// Reconnects counts successful dials after the first connection and
+	// DialFailures every dial which failed.
+	Reconnects   uint64
+	DialFailures uint64
+
+	Buffered  int
+	Connected bool
+}
+
+// NetWriter ships log output to a collector over a stream connection. Writes
+// never block on the network: they are queued and sent by a background
+// goroutine which reconnects with exponential backoff.
+type NetWriter struct {
+	network string
+	addr    string
+	opts    NetWriterOptions
+
+	mu          sync.Mutex
+	mem         [][]byte
+	spool       *os.File
+	spoolSize   int64
+	spoolOffset int64
+	closed      bool
+
+	conn      net.Conn
+	stopConn  func() bool
+	connected atomic.Bool
+
+	delivered    atomic.Uint64
+	dropped      atomic.Uint64
+	spooled      atomic.Uint64
+	reconnects   atomic.Uint64
+	dialFailures atomic.Uint64
+
+	notify  chan struct{}
+	closing chan struct{}
+	stopped chan struct{}
+
+	// ctx is cancelled once CloseTimeout ran out, interrupting dials and
+	// writes in progress.
+	ctx   context.Context
+	abort context.CancelFunc
+}
+
+func NewNetWriter(network, addr string, opts NetWriterOptions) (*NetWriter, error) {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1024
+	}
+
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 100 * time.Millisecond
+	}
+
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = 30 * time.Second
+	}
+
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = 10 * time.Second
+	}
+
+	if opts.CloseTimeout <= 0 {
+		opts.CloseTimeout = 5 * time.Second
+	}
+
+	w := &NetWriter{
+		network: network,
+		addr:    addr,
+		opts:    opts,
+		notify:  make(chan struct{}, 1),
+		closing: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	w.ctx, w.abort = context.WithCancel(context.Background())
+
+	if opts.SpoolPath != "" {
+		f, err := os.OpenFile(opts.SpoolPath, os.O_CREATE|os.O_RDWR, 0o600)
+		if err != nil {
+			w.abort()
+			return nil, err
+		}
+
+		info, err := f.Stat()
+		if err != nil {
+			w.abort()
+			_ = f.Close()
+
+			return nil, err
+		}
+
+		w.spool = f
+		w.spoolSize = info.Size()
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+func (w *NetWriter) Write(p []byte) (int, error) {
+	b := make([]byte, len(p))
+	copy(b, p)
+
+	w.mu.Lock()
+
+	switch {
+	case w.closed:
+		w.mu.Unlock()
+		w.dropped.Add(1)
+
+		return 0, ErrNetWriterClosed
+	case w.spoolSize == 0 && len(w.mem) < w.opts.BufferSize:
+		w.mem = append(w.mem, b)
+	case w.spool != nil:
+		if err := w.appendSpool(b); err != nil {
+			w.mu.Unlock()
+			w.dropped.Add(1)
+
+			return 0, err
+		}
+
+		w.spooled.Add(1)
+	default:
+		w.mu.Unlock()
+		w.dropped.Add(1)
+
+		return 0, ErrNetWriterDropped
+	}
+
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+
+	return len(p), nil
+}
+
+// Close waits up to CloseTimeout for buffered writes to be delivered and
+// closes the connection and spool file. Undelivered writes are kept in the
+// spool if there is one and counted as dropped otherwise.
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.closing)
+
+	select {
+	case <-w.stopped:
+	case <-time.After(w.opts.CloseTimeout):
+		w.abort()
+		<-w.stopped
+	}
+
+	w.abort()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.spool == nil {
+		w.dropped.Add(uint64(len(w.mem)))
+		w.mem = nil
+
+		return nil
+	}
+
+	err := w.prependSpool(w.mem)
+	if err != nil {
+		w.dropped.Add(uint64(len(w.mem)))
+	}
+
+	w.mem = nil
+
+	if closeErr := w.spool.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func (w *NetWriter) Stats() NetWriterStats {
+	w.mu.Lock()
+	buffered := len(w.mem)
+	w.mu.Unlock()
+
+	return NetWriterStats{
+		Delivered:    w.delivered.Load(),
+		Dropped:      w.dropped.Load(),
+		Spooled:      w.spooled.Load(),
+		Reconnects:   w.reconnects.Load(),
+		DialFailures: w.dialFailures.Load(),
+		Buffered:     buffered,
+		Connected:    w.connected.Load(),
+	}
+}
+
+func (w *NetWriter) run() {
+	defer close(w.stopped)
+	defer w.disconnect()
+
+	var (
+		backoff = w.opts.MinBackoff
+		dialed  bool
+	)
+
+	for {
+		b, fromSpool, ok := w.peek()
+		if !ok {
+			select {
+			case <-w.notify:
+				continue
+			case <-w.closing:
+				if _, _, ok := w.peek(); ok {
+					continue
+				}
+
+				return
+			case <-w.ctx.Done():
+				return
+			}
+		}
+
+		if w.conn == nil {
+			if err := w.connect(); err != nil {
+				w.dialFailures.Add(1)
+
+				select {
+				case <-time.After(backoff):
+				case <-w.ctx.Done():
+					return
+				}
+
+				backoff *= 2
+				if backoff > w.opts.MaxBackoff {
+					backoff = w.opts.MaxBackoff
+				}
+
+				continue
+			}
+
+			if dialed {
+				w.reconnects.Add(1)
+			}
+
+			dialed = true
+			backoff = w.opts.MinBackoff
+		}
+
+		_ = w.conn.SetWriteDeadline(time.Now().Add(w.opts.WriteTimeout))
+
+		if _, err := w.conn.Write(b); err != nil {
+			w.disconnect()
+			continue
+		}
+
+		w.pop(len(b), fromSpool)
+		w.delivered.Add(1)
+	}
+}
+
+func (w *NetWriter) connect() error {
+	dialer := &net.Dialer{Timeout: w.opts.DialTimeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if w.opts.TLSConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: w.opts.TLSConfig}
+		conn, err = tlsDialer.DialContext(w.ctx, w.network, w.addr)
+	} else {
+		conn, err = dialer.DialContext(w.ctx, w.network, w.addr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.conn = conn
+	w.stopConn = context.AfterFunc(w.ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	w.connected.Store(true)
+
+	return nil
+}
+
+func (w *NetWriter) disconnect() {
+	if w.conn == nil {
+		return
+	}
+
+	w.stopConn()
+	_ = w.conn.Close()
+	w.conn = nil
+	w.connected.Store(false)
+}
+
+// peek returns the oldest undelivered write. Spooled writes are newer than
+// those in memory, so the spool is only read once memory is empty. The spool
+// is read without holding w.mu as Write only appends behind spoolSize and
+// only the run goroutine moves spoolOffset.
+func (w *NetWriter) peek() ([]byte, bool, bool) {
+	w.mu.Lock()
+
+	if len(w.mem) > 0 {
+		b := w.mem[0]
+		w.mu.Unlock()
+
+		return b, false, true
+	}
+
+	if w.spool == nil || w.spoolOffset >= w.spoolSize {
+		w.mu.Unlock()
+		return nil, false, false
+	}
+
+	spool, offset, size := w.spool, w.spoolOffset, w.spoolSize
+	w.mu.Unlock()
+
+	b, err := readSpool(spool, offset, size)
+	if err == nil {
+		return b, true, true
+	}
+
+	// A truncated frame left by a crash, discard the spool up to where it
+	// ended when it was read and keep anything appended since.
+	w.dropped.Add(1)
+
+	w.mu.Lock()
+
+	if w.spoolSize == size {
+		w.resetSpool()
+		w.mu.Unlock()
+
+		return nil, false, false
+	}
+
+	w.spoolOffset = size
+	w.mu.Unlock()
+
+	return w.peek()
+}
+
+func (w *NetWriter) pop(size int, fromSpool bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !fromSpool {
+		w.mem[0] = nil
+		w.mem = w.mem[1:]
+
+		return
+	}
+
+	w.spoolOffset += int64(4 + size)
+	if w.spoolOffset >= w.spoolSize {
+		w.resetSpool()
+	}
+}
+
+func (w *NetWriter) appendSpool(b []byte) error {
+	frame := spoolFrame(b)
+
+	if _, err := w.spool.WriteAt(frame, w.spoolSize); err != nil {
+		return err
+	}
+
+	w.spoolSize += int64(len(frame))
+
+	return nil
+}
+
+// prependSpool writes mem in front of the undelivered part of the spool, as
+// writes still in memory are older than those already spooled.
+func (w *NetWriter) prependSpool(mem [][]byte) error {
+	if len(mem) == 0 {
+		return nil
+	}
+
+	var frames []byte
+	for _, b := range mem {
+		frames = append(frames, spoolFrame(b)...)
+	}
+
+	rest := make([]byte, w.spoolSize-w.spoolOffset)
+	if _, err := w.spool.ReadAt(rest, w.spoolOffset); err != nil {
+		return err
+	}
+
+	frames = append(frames, rest...)
+
+	if _, err := w.spool.WriteAt(frames, 0); err != nil {
+		return err
+	}
+
+	if err := w.spool.Truncate(int64(len(frames))); err != nil {
+		return err
+	}
+
+	w.spoolOffset = 0
+	w.spoolSize = int64(len(frames))
+
+	return nil
+}
+
+func spoolFrame(b []byte) []byte {
+	frame := make([]byte, 4+len(b))
+	binary.BigEndian.PutUint32(frame, uint32(len(b)))
+	copy(frame[4:], b)
+
+	return frame
+}
+
+// readSpool reads the frame at offset from a spool holding size bytes.
+func readSpool(spool *os.File, offset, size int64) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := spool.ReadAt(header, offset); err != nil {
+		return nil, err
+	}
+
+	n := int64(binary.BigEndian.Uint32(header))
+	if offset+4+n > size {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b := make([]byte, n)
+	if _, err := spool.ReadAt(b, offset+4); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (w *NetWriter) resetSpool() {
+	_ = w.spool.Truncate(0)
+	w.spoolSize = 0
+	w.spoolOffset = 0
+}
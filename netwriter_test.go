@@ -0,0 +1,356 @@
+package log
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testNetWriterOptions() NetWriterOptions {
+	return NetWriterOptions{
+		MinBackoff:   5 * time.Millisecond,
+		MaxBackoff:   20 * time.Millisecond,
+		CloseTimeout: 2 * time.Second,
+	}
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	return addr
+}
+
+// acceptLines accepts connections on ln and sends every line received.
+func acceptLines(ln net.Listener) chan string {
+	lines := make(chan string, 100)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+
+	return lines
+}
+
+func requireLines(t *testing.T, lines chan string, want ...string) {
+	t.Helper()
+
+	for _, w := range want {
+		select {
+		case got := <-lines:
+			require.Equal(t, w, got)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for %q", w)
+		}
+	}
+}
+
+func TestNetWriterDelivers(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lines := acceptLines(ln)
+
+	w, err := NewNetWriter("tcp", ln.Addr().String(), testNetWriterOptions())
+	require.NoError(t, err)
+	defer w.Close()
+
+	l := NewLogger()
+	l.SetOut(w)
+	l.SetHandler(JSONHandler)
+	l.SetDeterministic(true)
+
+	_, err = l.Info("first")
+	require.NoError(t, err)
+	_, err = l.Info("second")
+	require.NoError(t, err)
+
+	requireLines(
+		t,
+		lines,
+		`{"timestamp":"1970-01-01T00:00:00Z","level":"inf","msg":"first"}`,
+		`{"timestamp":"1970-01-01T00:00:01Z","level":"inf","msg":"second"}`,
+	)
+
+	require.Eventually(t, func() bool {
+		s := w.Stats()
+		return s.Delivered == 2 && s.Connected && s.Buffered == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestNetWriterBuffersWhileDisconnected(t *testing.T) {
+	addr := freeAddr(t)
+
+	w, err := NewNetWriter("tcp", addr, testNetWriterOptions())
+	require.NoError(t, err)
+	defer w.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := fmt.Fprintf(w, "line %d\n", i)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 5, w.Stats().Buffered)
+
+	ln, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	requireLines(t, acceptLines(ln), "line 0", "line 1", "line 2", "line 3", "line 4")
+}
+
+func TestNetWriterDropsWhenFull(t *testing.T) {
+	opts := testNetWriterOptions()
+	opts.BufferSize = 2
+	opts.CloseTimeout = 10 * time.Millisecond
+
+	w, err := NewNetWriter("tcp", freeAddr(t), opts)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := w.Write([]byte("kept\n"))
+		require.NoError(t, err)
+	}
+
+	_, err = w.Write([]byte("dropped\n"))
+	require.ErrorIs(t, err, ErrNetWriterDropped)
+
+	require.NoError(t, w.Close())
+
+	_, err = w.Write([]byte("closed\n"))
+	require.ErrorIs(t, err, ErrNetWriterClosed)
+
+	s := w.Stats()
+	require.Equal(t, uint64(4), s.Dropped)
+	require.Zero(t, s.Delivered)
+	require.Zero(t, s.Buffered)
+}
+
+func TestNetWriterCloseInterruptsWrite(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	// Accept the connection but never read from it.
+	conns := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			conns <- conn
+		}
+	}()
+
+	opts := testNetWriterOptions()
+	opts.WriteTimeout = time.Minute
+	opts.CloseTimeout = 50 * time.Millisecond
+
+	w, err := NewNetWriter("tcp", ln.Addr().String(), opts)
+	require.NoError(t, err)
+
+	_, err = w.Write(make([]byte, 64<<20))
+	require.NoError(t, err)
+
+	conn := <-conns
+	defer conn.Close()
+
+	start := time.Now()
+	require.NoError(t, w.Close())
+	require.Less(t, time.Since(start), 2*time.Second)
+	require.Equal(t, uint64(1), w.Stats().Dropped)
+}
+
+func TestNetWriterSpool(t *testing.T) {
+	addr := freeAddr(t)
+	spool := filepath.Join(t.TempDir(), "spool")
+
+	opts := testNetWriterOptions()
+	opts.BufferSize = 2
+	opts.SpoolPath = spool
+	opts.CloseTimeout = 10 * time.Millisecond
+
+	t.Run("spool survives restart", func(t *testing.T) {
+		w, err := NewNetWriter("tcp", addr, opts)
+		require.NoError(t, err)
+
+		for i := 0; i < 6; i++ {
+			_, err := fmt.Fprintf(w, "line %d\n", i)
+			require.NoError(t, err)
+		}
+
+		s := w.Stats()
+		require.Equal(t, uint64(4), s.Spooled)
+		require.Equal(t, 2, s.Buffered)
+
+		require.NoError(t, w.Close())
+
+		info, err := os.Stat(spool)
+		require.NoError(t, err)
+		require.Positive(t, info.Size())
+	})
+
+	t.Run("replay in order", func(t *testing.T) {
+		opts.CloseTimeout = 2 * time.Second
+
+		w, err := NewNetWriter("tcp", addr, opts)
+		require.NoError(t, err)
+
+		_, err = w.Write([]byte("line 6\n"))
+		require.NoError(t, err)
+
+		ln, err := net.Listen("tcp", addr)
+		require.NoError(t, err)
+		defer ln.Close()
+
+		requireLines(t, acceptLines(ln), "line 0", "line 1", "line 2", "line 3", "line 4", "line 5", "line 6")
+		require.NoError(t, w.Close())
+
+		info, err := os.Stat(spool)
+		require.NoError(t, err)
+		require.Zero(t, info.Size())
+	})
+}
+
+func TestNetWriterReconnects(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	conns := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			conns <- conn
+		}
+	}()
+
+	w, err := NewNetWriter("tcp", ln.Addr().String(), testNetWriterOptions())
+	require.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	first := <-conns
+	line, err := bufio.NewReader(first).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "first\n", line)
+	require.NoError(t, first.Close())
+
+	var second net.Conn
+
+	require.Eventually(t, func() bool {
+		_, _ = w.Write([]byte("again\n"))
+
+		select {
+		case second = <-conns:
+			return true
+		default:
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+
+	defer second.Close()
+
+	line, err = bufio.NewReader(second).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "again\n", line)
+
+	s := w.Stats()
+	require.Equal(t, uint64(1), s.Reconnects)
+	require.Zero(t, s.DialFailures)
+}
+
+func TestNetWriterDialFailures(t *testing.T) {
+	w, err := NewNetWriter("tcp", freeAddr(t), testNetWriterOptions())
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("pending\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return w.Stats().DialFailures >= 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	s := w.Stats()
+	require.Zero(t, s.Reconnects)
+	require.False(t, s.Connected)
+
+	require.NoError(t, w.Close())
+}
+
+func TestNetWriterTLS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	require.NoError(t, err)
+	defer ln.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	opts := testNetWriterOptions()
+	opts.TLSConfig = &tls.Config{RootCAs: pool}
+
+	w, err := NewNetWriter("tcp", ln.Addr().String(), opts)
+	require.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("secure\n"))
+	require.NoError(t, err)
+
+	requireLines(t, acceptLines(ln), "secure")
+}
@@ -0,0 +1,93 @@
+package log
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type JSONFormat int
+
+const (
+	JSONFormatDefault JSONFormat = iota
+
+	// JSONFormatOTel follows the field names and severity numbers of the
+	// OpenTelemetry log data model.
+	JSONFormatOTel
+)
+
+func (l *Logger) SetJSONFormat(format JSONFormat) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jsonFormat = format
+}
+
+func (l *Logger) GetJSONFormat() JSONFormat {
+	r := l.root()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.jsonFormat
+}
+
+type otelRecord struct {
+	Timestamp            string                 `json:"Timestamp"`
+	SeverityText         string                 `json:"SeverityText"`
+	SeverityNumber       int                    `json:"SeverityNumber"`
+	Body                 string                 `json:"Body"`
+	Attributes           map[string]interface{} `json:"Attributes,omitempty"`
+	TraceID              string                 `json:"TraceId,omitempty"`
+	SpanID               string                 `json:"SpanId,omitempty"`
+	InstrumentationScope *otelScope             `json:"InstrumentationScope,omitempty"`
+}
+
+type otelScope struct {
+	Name string `json:"Name"`
+}
+
+func (m *msg) marshalOTel() ([]byte, error) {
+	r := otelRecord{
+		Timestamp:      strconv.FormatInt(m.Timestamp.UnixNano(), 10),
+		SeverityText:   otelSeverityText(m.Level),
+		SeverityNumber: otelSeverityNumber(m.Level),
+		Body:           m.Msg,
+		Attributes:     jsonArgs(m.Args),
+		TraceID:        m.TraceID,
+		SpanID:         m.SpanID,
+	}
+
+	if m.Logger != "" {
+		r.InstrumentationScope = &otelScope{Name: m.Logger}
+	}
+
+	return json.Marshal(r)
+}
+
+func otelSeverityNumber(l Level) int {
+	switch l {
+	case LevelDebug:
+		return 5
+	case LevelInfo:
+		return 9
+	case LevelWarn:
+		return 13
+	case LevelError:
+		return 17
+	case LevelFatal:
+		return 21
+	default:
+		return 0
+	}
+}
+
+func otelSeverityText(l Level) string {
+	if otelSeverityNumber(l) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(levelName(l))
+}
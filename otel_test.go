@@ -0,0 +1,94 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerSetJSONFormat(t *testing.T) {
+	l := NewLogger()
+	require.Equal(t, JSONFormatDefault, l.GetJSONFormat())
+
+	l.Named("child").SetJSONFormat(JSONFormatOTel)
+	require.Equal(t, JSONFormatOTel, l.GetJSONFormat())
+}
+
+func TestLoggerOTelJSON(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(JSONHandler)
+	l.SetJSONFormat(JSONFormatOTel)
+	l.SetClock(FixedClock(time.Unix(1586960586, 0)))
+
+	t.Run("full record", func(t *testing.T) {
+		defer buf.Reset()
+
+		ctx := ContextWithTrace(context.Background(), "abc", "def")
+
+		_, _ = l.Named("db").WithContext(ctx).Warn("slow query", "rows", 2)
+		require.JSONEq(
+			t,
+			`{
+				"Timestamp": "1586960586000000000",
+				"SeverityText": "WARN",
+				"SeverityNumber": 13,
+				"Body": "slow query",
+				"Attributes": {"rows": 2},
+				"TraceId": "abc",
+				"SpanId": "def",
+				"InstrumentationScope": {"Name": "db"}
+			}`,
+			buf.String(),
+		)
+	})
+
+	t.Run("minimal record", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Info("test")
+
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		require.Equal(t, "INFO", got["SeverityText"])
+		require.NotContains(t, got, "Attributes")
+		require.NotContains(t, got, "TraceId")
+		require.NotContains(t, got, "InstrumentationScope")
+	})
+
+	t.Run("text handler unaffected", func(t *testing.T) {
+		defer buf.Reset()
+		defer l.SetHandler(JSONHandler)
+
+		l.SetHandler(TextHandler)
+		_, _ = l.Info("test")
+		require.Contains(t, buf.String(), "timestamp=")
+	})
+}
+
+func TestOTelSeverity(t *testing.T) {
+	tests := []struct {
+		level  Level
+		number int
+		text   string
+	}{
+		{LevelDebug, 5, "DEBUG"},
+		{LevelInfo, 9, "INFO"},
+		{LevelWarn, 13, "WARN"},
+		{LevelError, 17, "ERROR"},
+		{LevelFatal, 21, "FATAL"},
+		{LevelInvalid, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			require.Equal(t, tt.number, otelSeverityNumber(tt.level))
+			require.Equal(t, tt.text, otelSeverityText(tt.level))
+		})
+	}
+}
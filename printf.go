@@ -0,0 +1,67 @@
+package log
+
+import "fmt"
+
+func (l *Logger) Enabled(level Level) bool {
+	return evalLevel(level, l.GetLevel())
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) (int, error) {
+	return l.Logf(LevelDebug, format, args...)
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) (int, error) {
+	return l.Logf(LevelInfo, format, args...)
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) (int, error) {
+	return l.Logf(LevelWarn, format, args...)
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) (int, error) {
+	return l.Logf(LevelError, format, args...)
+}
+
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+
+	_, _ = l.write(LevelFatal, msg)
+	l.root().exit(msg)
+}
+
+func (l *Logger) Logf(level Level, format string, args ...interface{}) (int, error) {
+	if level == LevelFatal {
+		l.Fatalf(format, args...)
+		return 0, nil
+	}
+
+	if !l.Enabled(level) && !l.root().recording() {
+		return 0, nil
+	}
+
+	return l.log(level, fmt.Sprintf(format, args...))
+}
+
+// Logfw formats the message from format and args and appends fields as
+// key/value pairs. Since args is a slice, go vet cannot check the format.
+func (l *Logger) Logfw(
+	level Level,
+	format string,
+	args []interface{},
+	fields ...interface{},
+) (int, error) {
+	if level == LevelFatal {
+		msg := fmt.Sprintf(format, args...)
+
+		_, _ = l.write(LevelFatal, msg, fields...)
+		l.root().exit(msg)
+
+		return 0, nil
+	}
+
+	if !l.Enabled(level) && !l.root().recording() {
+		return 0, nil
+	}
+
+	return l.log(level, fmt.Sprintf(format, args...), fields...)
+}
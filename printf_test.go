@@ -0,0 +1,111 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingStringer struct {
+	calls *int
+}
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "expensive"
+}
+
+func TestLoggerPrintf(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetColor(false)
+
+	cases := []struct {
+		Name  string
+		Log   func(format string, args ...interface{}) (int, error)
+		Level string
+	}{
+		{Name: "debug", Log: l.Debugf, Level: "DBG"},
+		{Name: "info", Log: l.Infof, Level: "INF"},
+		{Name: "warn", Log: l.Warnf, Level: "WRN"},
+		{Name: "error", Log: l.Errorf, Level: "ERR"},
+	}
+
+	l.SetLevel(LevelDebug)
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			defer buf.Reset()
+
+			n, err := c.Log("%d items in %s", 3, "cart")
+			require.NoError(t, err)
+			require.NotZero(t, n)
+			require.Contains(t, buf.String(), `level=`+c.Level+` msg="3 items in cart"`)
+		})
+	}
+
+	t.Run("not formatted below level", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetLevel(LevelError)
+		defer l.SetLevel(LevelDebug)
+
+		calls := 0
+		n, err := l.Infof("%s", countingStringer{calls: &calls})
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Zero(t, calls)
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("logf", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Logf(LevelWarn, "retry %d", 2)
+		require.Contains(t, buf.String(), `level=WRN msg="retry 2"`)
+	})
+
+	t.Run("logfw", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Logfw(LevelInfo, "user %s logged in", []interface{}{"anton"}, "ip", "127.0.0.1")
+		require.Contains(t, buf.String(), `level=INF msg="user anton logged in" ip="127.0.0.1"`)
+	})
+}
+
+func TestLoggerFatalf(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetColor(false)
+
+	code := -1
+	l.SetExitFunc(func(c int) { code = c })
+
+	t.Run("fatalf", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.Fatalf("cannot bind %s", ":80")
+		require.Equal(t, 1, code)
+		require.Contains(t, buf.String(), `level=FTL msg="cannot bind :80"`)
+	})
+
+	t.Run("logf fatal exits", func(t *testing.T) {
+		defer buf.Reset()
+
+		code = -1
+		_, _ = l.Logf(LevelFatal, "bye")
+		require.Equal(t, 1, code)
+	})
+
+	t.Run("logfw fatal exits", func(t *testing.T) {
+		defer buf.Reset()
+
+		code = -1
+		_, _ = l.Logfw(LevelFatal, "bye %s", []interface{}{"now"}, "key", "value")
+		require.Equal(t, 1, code)
+		require.Contains(t, buf.String(), `msg="bye now" key="value"`)
+	})
+}
@@ -0,0 +1,293 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONProfile maps the built-in fields of the JSONHandler to the names and
+// values expected by a log ingestion platform. Fields with an empty key are
+// omitted. Profiles are ignored if the format is set to JSONFormatOTel.
+type JSONProfile struct {
+	TimeKey string
+
+	// TimeFormat is a layout for time.Format, RFC 3339 is used if empty.
+	TimeFormat string
+
+	LevelKey string
+
+	// LevelNames maps levels to the names written to LevelKey. Levels
+	// missing from the map use Level.String.
+	LevelNames map[Level]string
+
+	MessageKey string
+	LoggerKey  string
+	TraceIDKey string
+	SpanIDKey  string
+
+	// SourceKey enables capturing the caller of the log call. FormatSource
+	// converts it to the written value, Source.String is used if nil.
+	SourceKey    string
+	FormatSource func(s Source) interface{}
+
+	// FieldsKey nests fields below this key instead of the top level.
+	FieldsKey string
+
+	// Static fields are added to every record.
+	Static map[string]interface{}
+}
+
+type Source struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (s Source) String() string {
+	return s.File + ":" + strconv.Itoa(s.Line)
+}
+
+// DefaultJSONProfile returns the field names of the JSONHandler as a base
+// for custom profiles.
+func DefaultJSONProfile() *JSONProfile {
+	return &JSONProfile{
+		TimeKey:    "timestamp",
+		LevelKey:   "level",
+		MessageKey: "msg",
+		LoggerKey:  "logger",
+		TraceIDKey: TraceIDKey,
+		SpanIDKey:  SpanIDKey,
+	}
+}
+
+// GCPProfile follows the structured logging format of Google Cloud Logging.
+// Traces are only linked if the TraceExtractor returns trace ids in the form
+// projects/PROJECT_ID/traces/TRACE_ID.
+func GCPProfile() *JSONProfile {
+	return &JSONProfile{
+		TimeKey:  "time",
+		LevelKey: "severity",
+		LevelNames: map[Level]string{
+			LevelDebug: "DEBUG",
+			LevelInfo:  "INFO",
+			LevelWarn:  "WARNING",
+			LevelError: "ERROR",
+			LevelFatal: "CRITICAL",
+		},
+		TimeFormat: time.RFC3339Nano,
+		MessageKey: "message",
+		LoggerKey:  "logger",
+		TraceIDKey: "logging.googleapis.com/trace",
+		SpanIDKey:  "logging.googleapis.com/spanId",
+		SourceKey:  "logging.googleapis.com/sourceLocation",
+		FormatSource: func(s Source) interface{} {
+			return map[string]interface{}{
+				"file":     s.File,
+				"line":     strconv.Itoa(s.Line),
+				"function": s.Function,
+			}
+		},
+	}
+}
+
+// ECSProfile follows the Elastic Common Schema.
+func ECSProfile() *JSONProfile {
+	return &JSONProfile{
+		TimeKey:    "@timestamp",
+		TimeFormat: time.RFC3339Nano,
+		LevelKey:   "log.level",
+		LevelNames: map[Level]string{
+			LevelDebug: "debug",
+			LevelInfo:  "info",
+			LevelWarn:  "warn",
+			LevelError: "error",
+			LevelFatal: "fatal",
+		},
+		MessageKey: "message",
+		LoggerKey:  "log.logger",
+		TraceIDKey: "trace.id",
+		SpanIDKey:  "span.id",
+		SourceKey:  "log.origin",
+		FormatSource: func(s Source) interface{} {
+			return map[string]interface{}{
+				"file":     map[string]interface{}{"name": s.File, "line": s.Line},
+				"function": s.Function,
+			}
+		},
+		Static: map[string]interface{}{"ecs.version": "8.11.0"},
+	}
+}
+
+// AWSProfile follows the JSON log format of AWS Lambda and CloudWatch.
+func AWSProfile() *JSONProfile {
+	return &JSONProfile{
+		TimeKey:    "timestamp",
+		TimeFormat: time.RFC3339Nano,
+		LevelKey:   "level",
+		LevelNames: map[Level]string{
+			LevelDebug: "DEBUG",
+			LevelInfo:  "INFO",
+			LevelWarn:  "WARN",
+			LevelError: "ERROR",
+			LevelFatal: "FATAL",
+		},
+		MessageKey: "message",
+		LoggerKey:  "logger",
+		TraceIDKey: "traceId",
+		SpanIDKey:  "spanId",
+	}
+}
+
+// AzureProfile follows the field names of Azure Monitor and Application
+// Insights, correlating records by operation id.
+func AzureProfile() *JSONProfile {
+	return &JSONProfile{
+		TimeKey:    "time",
+		TimeFormat: time.RFC3339Nano,
+		LevelKey:   "level",
+		LevelNames: map[Level]string{
+			LevelDebug: "Verbose",
+			LevelInfo:  "Informational",
+			LevelWarn:  "Warning",
+			LevelError: "Error",
+			LevelFatal: "Critical",
+		},
+		MessageKey: "message",
+		LoggerKey:  "category",
+		TraceIDKey: "operation_Id",
+		SpanIDKey:  "operation_ParentId",
+	}
+}
+
+// SetJSONProfile sets the profile used by the JSONHandler. The default field
+// names are restored if p is nil.
+func (l *Logger) SetJSONProfile(p *JSONProfile) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profile = p
+}
+
+func (l *Logger) GetJSONProfile() *JSONProfile {
+	r := l.root()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.profile
+}
+
+func (m *msg) marshalProfile(p *JSONProfile) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+
+	written := make(map[string]bool)
+
+	write := func(key string, value interface{}) {
+		if key == "" || written[key] {
+			return
+		}
+
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			jsonValue, _ = json.Marshal(err.Error())
+		}
+
+		if len(written) > 0 {
+			buf.WriteString(",")
+		}
+
+		written[key] = true
+
+		jsonKey, _ := json.Marshal(key)
+		buf.Write(jsonKey)
+		buf.WriteString(":")
+		buf.Write(jsonValue)
+	}
+
+	layout := p.TimeFormat
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	write(p.TimeKey, m.Timestamp.Format(layout))
+
+	level, ok := p.LevelNames[m.Level]
+	if !ok {
+		level = m.Level.String()
+	}
+
+	write(p.LevelKey, level)
+
+	if m.Logger != "" {
+		write(p.LoggerKey, m.Logger)
+	}
+
+	write(p.MessageKey, m.Msg)
+
+	if m.TraceID != "" {
+		write(p.TraceIDKey, m.TraceID)
+	}
+
+	if m.SpanID != "" {
+		write(p.SpanIDKey, m.SpanID)
+	}
+
+	if m.Source != nil {
+		if p.FormatSource != nil {
+			write(p.SourceKey, p.FormatSource(*m.Source))
+		} else {
+			write(p.SourceKey, m.Source.String())
+		}
+	}
+
+	for _, key := range sortedKeys(p.Static) {
+		write(key, p.Static[key])
+	}
+
+	if p.FieldsKey != "" {
+		if len(m.Args) > 0 {
+			write(p.FieldsKey, jsonArgs(m.Args))
+		}
+	} else {
+		for _, key := range sortedKeys(m.Args) {
+			write(key, jsonArg(m.Args[key]))
+		}
+	}
+
+	buf.WriteString("}")
+
+	return buf.Bytes(), nil
+}
+
+var packageDir string = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callerSource returns the location of the first caller outside of this
+// package.
+func callerSource() *Source {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		if filepath.Dir(frame.File) != packageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return &Source{Function: frame.Function, File: frame.File, Line: frame.Line}
+		}
+
+		if !more {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerSetJSONProfile(t *testing.T) {
+	l := NewLogger()
+	require.Nil(t, l.GetJSONProfile())
+
+	p := GCPProfile()
+	l.Named("child").SetJSONProfile(p)
+	require.Same(t, p, l.GetJSONProfile())
+
+	l.SetJSONProfile(nil)
+	require.Nil(t, l.GetJSONProfile())
+}
+
+func TestJSONProfiles(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 12, 0, 0, 500, time.UTC)
+	ctx := ContextWithTrace(context.Background(), "abc", "def")
+
+	tests := []struct {
+		name    string
+		profile *JSONProfile
+		want    string
+	}{
+		{
+			name:    "default",
+			profile: DefaultJSONProfile(),
+			want: `{"timestamp":"2024-05-06T12:00:00Z","level":"wrn","logger":"db","msg":"test",` +
+				`"trace_id":"abc","span_id":"def","key":"value"}`,
+		},
+		{
+			name:    "aws",
+			profile: AWSProfile(),
+			want: `{"timestamp":"2024-05-06T12:00:00.0000005Z","level":"WARN","logger":"db","message":"test",` +
+				`"traceId":"abc","spanId":"def","key":"value"}`,
+		},
+		{
+			name:    "azure",
+			profile: AzureProfile(),
+			want: `{"time":"2024-05-06T12:00:00.0000005Z","level":"Warning","category":"db","message":"test",` +
+				`"operation_Id":"abc","operation_ParentId":"def","key":"value"}`,
+		},
+		{
+			name: "custom",
+			profile: &JSONProfile{
+				TimeKey:    "ts",
+				TimeFormat: time.DateOnly,
+				LevelKey:   "lvl",
+				LevelNames: map[Level]string{LevelWarn: "warning"},
+				MessageKey: "text",
+				FieldsKey:  "fields",
+				Static:     map[string]interface{}{"service": "api"},
+			},
+			want: `{"ts":"2024-05-06","lvl":"warning","text":"test","service":"api","fields":{"key":"value"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLogger()
+			buf := &bytes.Buffer{}
+			l.SetOut(buf)
+			l.SetHandler(JSONHandler)
+			l.SetClock(FixedClock(ts))
+			l.SetJSONProfile(tt.profile)
+
+			_, _ = l.Named("db").WithContext(ctx).Warn("test", "key", "value")
+			require.Equal(t, tt.want+"\n", buf.String())
+		})
+	}
+}
+
+func TestJSONProfileSource(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(JSONHandler)
+
+	t.Run("gcp", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetJSONProfile(GCPProfile())
+
+		_, file, line, _ := runtime.Caller(0)
+		_, _ = l.Error("test")
+
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		require.Equal(t, "ERROR", got["severity"])
+		require.Equal(t, "test", got["message"])
+		require.Equal(
+			t,
+			map[string]interface{}{
+				"file":     file,
+				"line":     strconv.Itoa(line + 1),
+				"function": "github.com/devusSs/log.TestJSONProfileSource.func1",
+			},
+			got["logging.googleapis.com/sourceLocation"],
+		)
+	})
+
+	t.Run("ecs", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetJSONProfile(ECSProfile())
+
+		_, file, line, _ := runtime.Caller(0)
+		_, _ = l.Infof("test %d", 1)
+
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		require.Equal(t, "info", got["log.level"])
+		require.Equal(t, "test 1", got["message"])
+		require.Equal(t, "8.11.0", got["ecs.version"])
+		require.Equal(
+			t,
+			map[string]interface{}{"name": file, "line": float64(line + 1)},
+			got["log.origin"].(map[string]interface{})["file"],
+		)
+	})
+
+	t.Run("string source", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetJSONProfile(&JSONProfile{MessageKey: "msg", SourceKey: "caller"})
+
+		_, file, line, _ := runtime.Caller(0)
+		_, _ = l.Info("test")
+
+		require.Equal(t, `{"msg":"test","caller":"`+file+":"+strconv.Itoa(line+1)+`"}`+"\n", buf.String())
+	})
+}
+
+func TestJSONProfileFieldCollision(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(JSONHandler)
+	l.SetJSONProfile(&JSONProfile{MessageKey: "message"})
+
+	_, _ = l.Info("test", "message", "field", "other", 1)
+	require.Equal(t, `{"message":"test","other":1}`+"\n", buf.String())
+}
@@ -0,0 +1,251 @@
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrMalformedLine error = errors.New("malformed log line")
+
+const maxLineSize = 16 * 1024 * 1024
+
+// ParseError reports a line which could not be decoded by a Reader.
+type ParseError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader decodes lines written by the TextHandler or JSONHandler back into
+// records. Lines starting with "{" are decoded as JSON, others as logfmt.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	return &Reader{scanner: scanner}
+}
+
+// Read returns the next record, skipping empty lines. Malformed lines are
+// reported as *ParseError and reading may continue after them. At the end
+// of the input Read returns io.EOF.
+func (r *Reader) Read() (Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		text := r.scanner.Text()
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		record, err := ParseLine(text)
+		if err != nil {
+			return Record{}, &ParseError{Line: r.line, Text: text, Err: err}
+		}
+
+		return record, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+
+	return Record{}, io.EOF
+}
+
+var ansiEscape *regexp.Regexp = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// ParseLine decodes a single line written by the TextHandler, including
+// colored output, or the JSONHandler with its default field names.
+func ParseLine(line string) (Record, error) {
+	line = strings.TrimSpace(ansiEscape.ReplaceAllString(line, ""))
+
+	if strings.HasPrefix(line, "{") {
+		return parseJSONLine(line)
+	}
+
+	return parseTextLine(line)
+}
+
+func parseJSONLine(line string) (Record, error) {
+	var fields map[string]interface{}
+
+	d := json.NewDecoder(strings.NewReader(line))
+	d.UseNumber()
+
+	if err := d.Decode(&fields); err != nil {
+		return Record{}, err
+	}
+
+	return recordFromFields(jsonNumbers(fields).(Fields))
+}
+
+// jsonNumbers replaces json.Number values in v by an int if they are
+// integral and a float64 otherwise. Objects become Fields so groups are
+// written as groups again.
+func jsonNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return int(i)
+		}
+
+		f, _ := value.Float64()
+
+		return f
+	case map[string]interface{}:
+		fields := make(Fields, len(value))
+		for key, item := range value {
+			fields[key] = jsonNumbers(item)
+		}
+
+		return fields
+	case []interface{}:
+		for i, item := range value {
+			value[i] = jsonNumbers(item)
+		}
+	}
+
+	return v
+}
+
+func parseTextLine(line string) (Record, error) {
+	fields := make(map[string]interface{})
+
+	for line != "" {
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || strings.ContainsAny(line[:eq], " \"") {
+			return Record{}, ErrMalformedLine
+		}
+
+		key := line[:eq]
+		line = line[eq+1:]
+
+		if strings.HasPrefix(line, `"`) {
+			// Values are quoted but not escaped, so a value ends at the
+			// first quote followed by a space or the end of the line.
+			end := strings.Index(line[1:], `" `)
+			if end < 0 {
+				if !strings.HasSuffix(line, `"`) || len(line) < 2 {
+					return Record{}, ErrMalformedLine
+				}
+
+				end = len(line) - 2
+			}
+
+			fields[key] = line[1 : end+1]
+			line = strings.TrimLeft(line[end+2:], " ")
+
+			continue
+		}
+
+		end := unquotedEnd(line)
+
+		fields[key] = textValue(line[:end])
+		line = strings.TrimLeft(line[end:], " ")
+	}
+
+	return recordFromFields(fields)
+}
+
+// unquotedEnd returns the end of the unquoted value at the start of line.
+// Values are formatted with %v, so slices, maps and structs like [1 2] or
+// {Anton 45} contain spaces which only end the value outside of brackets.
+func unquotedEnd(line string) int {
+	depth := 0
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '[', '{', '(':
+			depth++
+		case ']', '}', ')':
+			if depth > 0 {
+				depth--
+			}
+		case ' ':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(line)
+}
+
+// textValue converts an unquoted logfmt value to an int, float64 or bool if
+// possible.
+func textValue(s string) interface{} {
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+
+	// ParseFloat also accepts "inf" and "nan", which are level names and
+	// words rather than numbers here.
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strings.ContainsAny(s, "0123456789") {
+		return f
+	}
+
+	if s == "true" || s == "false" {
+		return s == "true"
+	}
+
+	return s
+}
+
+func recordFromFields(fields map[string]interface{}) (Record, error) {
+	var r Record
+
+	level, ok := fields["level"].(string)
+	if !ok {
+		return r, ErrMalformedLine
+	}
+
+	l, err := ParseLevel(level)
+	if err != nil {
+		return r, err
+	}
+
+	r.Level = l
+
+	if ts, ok := fields["timestamp"].(string); ok {
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return r, err
+		}
+
+		r.Time = t
+	}
+
+	r.Message, _ = fields["msg"].(string)
+	r.Logger, _ = fields["logger"].(string)
+
+	delete(fields, "timestamp")
+	delete(fields, "level")
+	delete(fields, "msg")
+	delete(fields, "logger")
+
+	if len(fields) > 0 {
+		r.Fields = fields
+	}
+
+	return r, nil
+}
@@ -0,0 +1,237 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		Name string
+		Line string
+		Want Record
+	}{
+		{
+			Name: "json",
+			Line: `{"timestamp":"2024-05-06T12:00:00Z","level":"wrn","logger":"db","msg":"test","n":1,"f":1.5,` +
+				`"req":{"id":"a","ids":[1,2]}}`,
+			Want: Record{
+				Time:    ts,
+				Level:   LevelWarn,
+				Logger:  "db",
+				Message: "test",
+				Fields: map[string]interface{}{
+					"n":   1,
+					"f":   1.5,
+					"req": Fields{"id": "a", "ids": []interface{}{1, 2}},
+				},
+			},
+		},
+		{
+			Name: "text",
+			Line: `timestamp=2024-05-06T12:00:00Z level=ERR logger=db msg="query failed" ok=false ms=2.5 n=3 ` +
+				`q="select 1" d=1s`,
+			Want: Record{
+				Time:    ts,
+				Level:   LevelError,
+				Logger:  "db",
+				Message: "query failed",
+				Fields:  map[string]interface{}{"ok": false, "ms": 2.5, "n": 3, "q": "select 1", "d": "1s"},
+			},
+		},
+		{
+			Name: "text composite values",
+			Line: `timestamp=2024-05-06T12:00:00Z level=INF msg="test" ids=[1 2] m=map[a:1 b:[x y]] ` +
+				`user={Anton 45} p=&{Anton {Berlin 10115}} n=1`,
+			Want: Record{
+				Time:    ts,
+				Level:   LevelInfo,
+				Message: "test",
+				Fields: map[string]interface{}{
+					"ids":  "[1 2]",
+					"m":    "map[a:1 b:[x y]]",
+					"user": "{Anton 45}",
+					"p":    "&{Anton {Berlin 10115}}",
+					"n":    1,
+				},
+			},
+		},
+		{
+			Name: "text colored",
+			Line: "timestamp=2024-05-06T12:00:00Z level=\033[36mINF\033[0m msg=\"test\"",
+			Want: Record{Time: ts, Level: LevelInfo, Message: "test"},
+		},
+		{
+			Name: "text quote in value",
+			Line: `timestamp=2024-05-06T12:00:00Z level=INF msg="say "hi"" key="" word=nan`,
+			Want: Record{
+				Time:    ts,
+				Level:   LevelInfo,
+				Message: `say "hi"`,
+				Fields:  map[string]interface{}{"key": "", "word": "nan"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			got, err := ParseLine(c.Line)
+			require.NoError(t, err)
+			require.Equal(t, c.Want, got)
+		})
+	}
+}
+
+func TestParseLineMalformed(t *testing.T) {
+	cases := []struct {
+		Name string
+		Line string
+		Err  error
+	}{
+		{Name: "plain text", Line: "panic: something went wrong", Err: ErrMalformedLine},
+		{Name: "missing level", Line: `msg="test"`, Err: ErrMalformedLine},
+		{Name: "unknown level", Line: `level=abc msg="test"`, Err: ErrInvalidLevel},
+		{Name: "unterminated quote", Line: `level=INF msg="test`, Err: ErrMalformedLine},
+		{Name: "invalid json", Line: `{"level":`},
+		{Name: "invalid timestamp", Line: `timestamp=yesterday level=INF`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			_, err := ParseLine(c.Line)
+			require.Error(t, err)
+
+			if c.Err != nil {
+				require.ErrorIs(t, err, c.Err)
+			}
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	in := strings.NewReader(`{"timestamp":"2024-05-06T12:00:00Z","level":"inf","msg":"first"}
+
+panic: something went wrong
+timestamp=2024-05-06T12:00:01Z level=WRN msg="second"
+`)
+
+	r := NewReader(in)
+
+	record, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, "first", record.Message)
+
+	_, err = r.Read()
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, 3, parseErr.Line)
+	require.Equal(t, "panic: something went wrong", parseErr.Text)
+	require.ErrorIs(t, err, ErrMalformedLine)
+	require.Equal(t, "line 3: malformed log line", err.Error())
+
+	record, err = r.Read()
+	require.NoError(t, err)
+	require.Equal(t, "second", record.Message)
+	require.Equal(t, LevelWarn, record.Level)
+
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReaderTextCompositeValues(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+
+	user := struct {
+		Name string
+		Age  int
+	}{Name: "Anton", Age: 45}
+
+	_, _ = l.Info("x", "ids", []int{1, 2}, "user", user, "m", map[string]int{"a": 1, "b": 2})
+
+	record, err := NewReader(buf).Read()
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"ids": "[1 2]", "user": "{Anton 45}", "m": "map[a:1 b:2]"}, record.Fields)
+}
+
+func TestReaderGroupedJSON(t *testing.T) {
+	record, err := ParseLine(`{"level":"inf","msg":"req","http":{"method":"GET","status":500}}`)
+	require.NoError(t, err)
+	require.Equal(t, Fields{"method": "GET", "status": 500}, record.Fields["http"])
+
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetColor(false)
+
+	_, err = l.WriteRecord(record)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), `level=INF msg="req" http.method="GET" http.status=500`)
+
+	buf.Reset()
+	l.SetHandler(JSONHandler)
+
+	_, err = l.WriteRecord(record)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), `"msg":"req","http":{"method":"GET","status":500}}`)
+}
+
+func TestReaderRoundTrip(t *testing.T) {
+	for _, handler := range []Handler{TextHandler, JSONHandler} {
+		t.Run(handler.String(), func(t *testing.T) {
+			l := NewLogger()
+			buf := &bytes.Buffer{}
+			l.SetOut(buf)
+			l.SetHandler(handler)
+			l.SetLevel(LevelDebug)
+			l.SetClock(MonotonicClock(time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC), time.Second))
+
+			_, _ = l.Debug("debug", "n", 1)
+			_, _ = l.Named("db").Warn("slow query", "ms", 2.5, "ok", true)
+			_, _ = l.Error("failed", "error", "broken pipe")
+
+			r := NewReader(buf)
+
+			var got []Record
+
+			for {
+				record, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+
+				require.NoError(t, err)
+
+				got = append(got, record)
+			}
+
+			ts := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+			require.Equal(t, []Record{
+				{Time: ts, Level: LevelDebug, Message: "debug", Fields: map[string]interface{}{"n": 1}},
+				{
+					Time:    ts.Add(time.Second),
+					Level:   LevelWarn,
+					Logger:  "db",
+					Message: "slow query",
+					Fields:  map[string]interface{}{"ms": 2.5, "ok": true},
+				},
+				{
+					Time:    ts.Add(2 * time.Second),
+					Level:   LevelError,
+					Message: "failed",
+					Fields:  map[string]interface{}{"error": "broken pipe"},
+				},
+			}, got)
+		})
+	}
+}
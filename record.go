@@ -0,0 +1,53 @@
+package log
+
+import "time"
+
+type Record struct {
+	Time    time.Time
+	Level   Level
+	Logger  string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// RecordWriter may be implemented by outputs passed to SetOut to receive
+// structured records instead of formatted lines.
+type RecordWriter interface {
+	WriteRecord(r Record) (int, error)
+}
+
+func (r Record) String() string {
+	return msgFromRecord(r).text(plainTheme)
+}
+
+func (m *msg) record() Record {
+	return Record{
+		Time:    m.Timestamp,
+		Level:   m.Level,
+		Logger:  m.Logger,
+		Message: m.Msg,
+		Fields:  m.fields(),
+	}
+}
+
+func msgFromRecord(r Record) *msg {
+	return &msg{
+		Timestamp: r.Time,
+		Level:     r.Level,
+		Logger:    r.Logger,
+		Msg:       r.Message,
+		Args:      r.Fields,
+	}
+}
+
+// WriteRecord writes r with the handler and output of l if r.Level is
+// enabled for r.Logger, e.g. to replay or convert records.
+func (l *Logger) WriteRecord(r Record) (int, error) {
+	root := l.root()
+
+	if !evalLevel(r.Level, root.levelFor(r.Logger)) {
+		return 0, nil
+	}
+
+	return root.emit(msgFromRecord(r))
+}
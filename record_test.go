@@ -0,0 +1,84 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordSlice []Record
+
+func (s *recordSlice) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (s *recordSlice) WriteRecord(r Record) (int, error) {
+	*s = append(*s, r)
+	return 1, nil
+}
+
+func TestRecordString(t *testing.T) {
+	r := Record{
+		Time:    time.Time{},
+		Level:   LevelWarn,
+		Logger:  "db",
+		Message: "test",
+		Fields:  map[string]interface{}{"key": "value"},
+	}
+
+	expected := `timestamp=0001-01-01T00:00:00Z level=WRN logger=db msg="test" key="value"`
+	require.Equal(t, expected, r.String())
+}
+
+func TestLoggerRecordWriter(t *testing.T) {
+	l := NewLogger()
+	records := &recordSlice{}
+	l.SetOut(records)
+
+	n, err := l.With("key", "value").Warn("test", "count", 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	require.Len(t, *records, 1)
+	require.Equal(t, LevelWarn, (*records)[0].Level)
+	require.Equal(t, "test", (*records)[0].Message)
+	require.Equal(t, map[string]interface{}{"key": "value", "count": 1}, (*records)[0].Fields)
+}
+
+func TestLoggerWriteRecord(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetColor(false)
+	l.SetComponentLevel("db", LevelError)
+
+	r := Record{
+		Time:    time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC),
+		Level:   LevelWarn,
+		Message: "test",
+		Fields:  map[string]interface{}{"key": "value"},
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, err := l.WriteRecord(r)
+		require.NoError(t, err)
+		require.Equal(t, `timestamp=2024-05-06T12:00:00Z level=WRN msg="test" key="value"`+"\n", buf.String())
+	})
+
+	t.Run("disabled for component", func(t *testing.T) {
+		defer buf.Reset()
+
+		r := r
+		r.Logger = "db.query"
+
+		n, err := l.WriteRecord(r)
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Empty(t, buf.String())
+	})
+
+}
@@ -0,0 +1,116 @@
+package log
+
+import "sync"
+
+const FlightRecorderKey = "flight_recorder"
+
+type flightRecorder struct {
+	mu   sync.Mutex
+	msgs []*msg
+	next int
+	full bool
+}
+
+// SetFlightRecorder keeps the last size records of all levels, including
+// those below the level threshold, in memory. They are written, tagged with
+// FlightRecorderKey, before every error or fatal record and on
+// FlushFlightRecorder. A size of zero or less disables the recorder.
+func (l *Logger) SetFlightRecorder(size int) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if size <= 0 {
+		r.recorder = nil
+		return
+	}
+
+	r.recorder = &flightRecorder{msgs: make([]*msg, size)}
+}
+
+func (l *Logger) FlushFlightRecorder() (int, error) {
+	r := l.root()
+
+	r.mu.RLock()
+	rec := r.recorder
+	r.mu.RUnlock()
+
+	if rec == nil {
+		return 0, nil
+	}
+
+	var (
+		total int
+		err   error
+	)
+
+	for _, m := range rec.drain() {
+		args := make(map[string]interface{}, len(m.Args)+1)
+		for k, v := range m.Args {
+			args[k] = v
+		}
+
+		args[FlightRecorderKey] = true
+		m.Args = args
+
+		n, writeErr := r.emit(m)
+		total += n
+
+		if writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+
+	return total, err
+}
+
+func (l *Logger) recording() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.recorder != nil
+}
+
+func (l *Logger) record(m *msg) {
+	l.mu.RLock()
+	rec := l.recorder
+	l.mu.RUnlock()
+
+	if rec != nil {
+		rec.add(m)
+	}
+}
+
+func (f *flightRecorder) add(m *msg) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.msgs[f.next] = m
+	f.next = (f.next + 1) % len(f.msgs)
+
+	if f.next == 0 {
+		f.full = true
+	}
+}
+
+func (f *flightRecorder) drain() []*msg {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var out []*msg
+	if f.full {
+		out = append(out, f.msgs[f.next:]...)
+	}
+
+	out = append(out, f.msgs[:f.next]...)
+
+	for i := range f.msgs {
+		f.msgs[i] = nil
+	}
+
+	f.next = 0
+	f.full = false
+
+	return out
+}
@@ -0,0 +1,93 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightRecorder(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetColor(false)
+	l.SetFlightRecorder(3)
+
+	lines := func() []string {
+		return strings.Split(strings.TrimSpace(buf.String()), "\n")
+	}
+
+	t.Run("below threshold not written", func(t *testing.T) {
+		n, err := l.Debug("debug 1")
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		_, _ = l.Debugf("debug %d", 2)
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("flushed on error", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Info("info 3")
+		_, _ = l.Named("db").Debug("debug 4", "key", "value")
+		_, _ = l.Error("error 5")
+
+		out := lines()
+		require.Len(t, out, 5)
+		require.Contains(t, out[0], `level=INF msg="info 3"`)
+		require.NotContains(t, out[0], FlightRecorderKey)
+		require.Contains(t, out[1], `level=DBG msg="debug 2" flight_recorder=true`)
+		require.Contains(t, out[2], `level=INF msg="info 3" flight_recorder=true`)
+		require.Contains(t, out[3], `level=DBG logger=db msg="debug 4" flight_recorder=true key="value"`)
+		require.Contains(t, out[4], `level=ERR msg="error 5"`)
+		require.NotContains(t, out[4], FlightRecorderKey)
+	})
+
+	t.Run("buffer is cleared after flush", func(t *testing.T) {
+		defer buf.Reset()
+
+		n, err := l.FlushFlightRecorder()
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("flush on demand", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Debug("debug 6")
+
+		n, err := l.FlushFlightRecorder()
+		require.NoError(t, err)
+		require.NotZero(t, n)
+		require.Contains(t, buf.String(), `msg="debug 6" flight_recorder=true`)
+	})
+
+	t.Run("flushed on fatal", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetExitFunc(func(int) {})
+		defer l.SetExitFunc(nil)
+
+		_, _ = l.Debug("debug 7")
+		l.Fatal("fatal 8")
+
+		out := lines()
+		require.Len(t, out, 2)
+		require.Contains(t, out[0], `msg="debug 7" flight_recorder=true`)
+		require.Contains(t, out[1], `level=FTL msg="fatal 8"`)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetFlightRecorder(0)
+
+		_, _ = l.Debug("debug 9")
+		_, _ = l.Error("error 10")
+
+		require.Len(t, lines(), 1)
+	})
+}
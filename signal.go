@@ -0,0 +1,14 @@
+package log
+
+func (l *Logger) stepLevel(delta int) {
+	from := l.GetLevel()
+
+	to := from + Level(delta)
+	if to < LevelDebug || to > LevelFatal {
+		return
+	}
+
+	l.SetLevel(to)
+
+	_, _ = l.write(LevelInfo, "log level changed", "from", levelName(from), "to", levelName(to))
+}
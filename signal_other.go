@@ -0,0 +1,8 @@
+//go:build !unix
+
+package log
+
+// HandleLevelSignals is a no-op on platforms without SIGUSR1 and SIGUSR2.
+func (l *Logger) HandleLevelSignals() (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerStepLevel(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetLevel(LevelFatal)
+
+	t.Run("step down logs transition", func(t *testing.T) {
+		l.stepLevel(-1)
+
+		require.Equal(t, LevelError, l.GetLevel())
+		require.Contains(t, buf.String(), `from="fatal" to="error"`)
+	})
+
+	t.Run("stop at debug", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			l.stepLevel(-1)
+		}
+
+		require.Equal(t, LevelDebug, l.GetLevel())
+	})
+
+	t.Run("stop at fatal", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			l.stepLevel(1)
+		}
+
+		require.Equal(t, LevelFatal, l.GetLevel())
+	})
+
+	t.Run("named logger steps its component", func(t *testing.T) {
+		db := l.Named("db")
+		db.stepLevel(-1)
+
+		require.Equal(t, LevelError, db.GetLevel())
+		require.Equal(t, LevelFatal, l.GetLevel())
+	})
+}
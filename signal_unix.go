@@ -0,0 +1,43 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// HandleLevelSignals lowers the level towards debug on SIGUSR1 and raises it
+// towards fatal on SIGUSR2 until the returned stop function is called.
+func (l *Logger) HandleLevelSignals() (stop func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case sig := <-c:
+				if sig == syscall.SIGUSR1 {
+					l.stepLevel(-1)
+				} else {
+					l.stepLevel(1)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
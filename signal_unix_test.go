@@ -0,0 +1,43 @@
+//go:build unix
+
+package log
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerHandleLevelSignals(t *testing.T) {
+	l := NewLogger()
+	lines := make(chanWriter, 1)
+	l.SetOut(lines)
+
+	stop := l.HandleLevelSignals()
+	defer stop()
+
+	// Wait for the change notice rather than the level alone, otherwise
+	// the handler may still be writing when the test returns.
+	receive := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(time.Second):
+			require.FailNow(t, "no level change logged")
+			return ""
+		}
+	}
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	require.Contains(t, receive(), `to="debug"`)
+	require.Equal(t, LevelDebug, l.GetLevel())
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	require.Contains(t, receive(), `to="info"`)
+	require.Equal(t, LevelInfo, l.GetLevel())
+
+	stop()
+	require.NotPanics(t, stop)
+}
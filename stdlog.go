@@ -0,0 +1,59 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	stdlog "log"
+)
+
+type levelWriter struct {
+	logger *Logger
+	level  Level
+}
+
+// Writer returns a writer which logs every line written to it at the given
+// level. Each call to Write is expected to contain complete lines, which
+// holds for the standard library logger.
+func (l *Logger) Writer(level Level) io.Writer {
+	return &levelWriter{logger: l, level: level}
+}
+
+func (l *Logger) StdLogger(level Level) *stdlog.Logger {
+	return stdlog.New(l.Writer(level), "", 0)
+}
+
+func (l *Logger) RedirectStdLog(level Level) (restore func()) {
+	out := stdlog.Writer()
+	flags := stdlog.Flags()
+	prefix := stdlog.Prefix()
+
+	stdlog.SetOutput(l.Writer(level))
+	stdlog.SetFlags(0)
+	stdlog.SetPrefix("")
+
+	return func() {
+		stdlog.SetOutput(out)
+		stdlog.SetFlags(flags)
+		stdlog.SetPrefix(prefix)
+	}
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		if w.level == LevelFatal {
+			_, _ = w.logger.write(LevelFatal, string(line))
+			continue
+		}
+
+		if _, err := w.logger.log(w.level, string(line)); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"bytes"
+	stdlog "log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerWriter(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(JSONHandler)
+
+	t.Run("one record per line", func(t *testing.T) {
+		defer buf.Reset()
+
+		n, err := l.Writer(LevelWarn).Write([]byte("first\r\n\nsecond\n"))
+		require.NoError(t, err)
+		require.Equal(t, 15, n)
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+		require.Contains(t, string(lines[0]), `"level":"wrn","msg":"first"`)
+		require.Contains(t, string(lines[1]), `"level":"wrn","msg":"second"`)
+	})
+
+	t.Run("level below threshold", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, err := l.Writer(LevelDebug).Write([]byte("hidden\n"))
+		require.NoError(t, err)
+		require.Empty(t, buf.String())
+	})
+}
+
+func TestLoggerStdLogger(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(JSONHandler)
+
+	l.StdLogger(LevelError).Printf("listen %s: failed", ":80")
+
+	require.Contains(t, buf.String(), `"level":"err","msg":"listen :80: failed"`)
+}
+
+func TestLoggerRedirectStdLog(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetHandler(JSONHandler)
+
+	flags := stdlog.Flags()
+
+	restore := l.RedirectStdLog(LevelInfo)
+	stdlog.Print("from the standard library")
+	restore()
+
+	require.Contains(t, buf.String(), `"level":"inf","msg":"from the standard library"`)
+	require.Equal(t, flags, stdlog.Flags())
+}
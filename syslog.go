@@ -0,0 +1,314 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type SyslogFormat int
+
+const (
+	RFC5424 SyslogFormat = iota
+	RFC3164
+)
+
+type Facility int
+
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	_
+	_
+	_
+	_
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+var ErrNoSyslog error = errors.New("no local syslog socket found")
+
+var syslogSockets []string = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogWriter sends records to a syslog daemon. Configure the exported
+// fields before passing the writer to SetOut.
+type SyslogWriter struct {
+	Format   SyslogFormat
+	Facility Facility
+	Hostname string
+	AppName  string
+
+	// SDID is the structured data id used for fields in RFC 5424 messages.
+	SDID string
+
+	network string
+	addr    string
+	pid     int
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogWriter connects to a syslog daemon over "udp", "tcp", "unix" or
+// "unixgram". If network and addr are empty the local syslog socket is used.
+func NewSyslogWriter(network, addr string, format SyslogFormat) (*SyslogWriter, error) {
+	hostname, _ := os.Hostname()
+
+	w := &SyslogWriter{
+		Format:   format,
+		Facility: FacilityUser,
+		Hostname: hostname,
+		AppName:  filepath.Base(os.Args[0]),
+		SDID:     "fields@32473",
+		network:  network,
+		addr:     addr,
+		pid:      os.Getpid(),
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+
+	err := w.send(w.format(Record{Time: time.Now(), Level: LevelInfo, Message: msg}))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *SyslogWriter) WriteRecord(r Record) (int, error) {
+	line := w.format(r)
+
+	if err := w.send(line); err != nil {
+		return 0, err
+	}
+
+	return len(line), nil
+}
+
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
+
+func (w *SyslogWriter) connect() error {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	if w.network != "" || w.addr != "" {
+		conn, err := net.Dial(w.network, w.addr)
+		if err != nil {
+			return err
+		}
+
+		w.conn = conn
+
+		return nil
+	}
+
+	for _, path := range syslogSockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.Dial(network, path)
+			if err == nil {
+				w.network = network
+				w.addr = path
+				w.conn = conn
+
+				return nil
+			}
+		}
+	}
+
+	return ErrNoSyslog
+}
+
+// send writes one message and reconnects once if the write fails. Messages
+// are framed by octet counting over TCP (RFC 6587) and terminated by a
+// newline on unix stream sockets, which is what local daemons expect.
+func (w *SyslogWriter) send(msg string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	switch w.network {
+	case "tcp", "tcp4", "tcp6":
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	case "unix":
+		msg += "\n"
+	}
+
+	if w.conn != nil {
+		if _, err := w.conn.Write([]byte(msg)); err == nil {
+			return nil
+		}
+	}
+
+	if err := w.connect(); err != nil {
+		return err
+	}
+
+	_, err := w.conn.Write([]byte(msg))
+
+	return err
+}
+
+func (w *SyslogWriter) format(r Record) string {
+	pri := int(w.Facility)*8 + syslogSeverity(r.Level)
+
+	hostname := syslogToken(w.Hostname, 255)
+	appName := syslogToken(w.AppName, 48)
+
+	if w.Format == RFC3164 {
+		msg := r.Message
+		if fields := formatArgs(r.Fields); fields != "" {
+			msg += " " + fields
+		}
+
+		return fmt.Sprintf(
+			"<%d>%s %s %s[%d]: %s",
+			pri,
+			r.Time.Format(time.Stamp),
+			hostname,
+			appName,
+			w.pid,
+			msg,
+		)
+	}
+
+	return fmt.Sprintf(
+		"<%d>1 %s %s %s %d %s %s %s",
+		pri,
+		r.Time.Format("2006-01-02T15:04:05.000000Z07:00"),
+		hostname,
+		appName,
+		w.pid,
+		syslogToken(r.Logger, 32),
+		w.structuredData(r.Fields),
+		r.Message,
+	)
+}
+
+func (w *SyslogWriter) structuredData(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return "-"
+	}
+
+	flat := make(map[string]interface{}, len(fields))
+	flattenFields("", fields, flat)
+
+	var b strings.Builder
+
+	b.WriteString("[")
+	b.WriteString(w.SDID)
+
+	for _, key := range sortedKeys(flat) {
+		b.WriteString(" ")
+		b.WriteString(sdName(key))
+		b.WriteString(`="`)
+		b.WriteString(sdValueEscaper.Replace(fmt.Sprintf("%v", flat[key])))
+		b.WriteString(`"`)
+	}
+
+	b.WriteString("]")
+
+	return b.String()
+}
+
+var sdValueEscaper *strings.Replacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+func sdName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+
+		return r
+	}, key)
+
+	if len(name) > 32 {
+		name = name[:32]
+	}
+
+	return name
+}
+
+// syslogToken returns s as a printable header field of at most maxLen bytes,
+// or the nil value "-" if s is empty.
+func syslogToken(s string, maxLen int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+
+		return r
+	}, s)
+
+	if s == "" {
+		return "-"
+	}
+
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+
+	return s
+}
+
+func syslogSeverity(l Level) int {
+	switch l {
+	case LevelDebug:
+		return 7
+	case LevelInfo:
+		return 6
+	case LevelWarn:
+		return 4
+	case LevelError:
+		return 3
+	case LevelFatal:
+		return 2
+	default:
+		return 5
+	}
+}
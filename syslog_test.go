@@ -0,0 +1,246 @@
+package log
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSyslogRecord() Record {
+	return Record{
+		Time:    time.Date(2024, 5, 6, 12, 30, 45, 123456000, time.UTC),
+		Level:   LevelWarn,
+		Logger:  "db",
+		Message: "slow query",
+		Fields: map[string]interface{}{
+			"table": "users",
+			"ms":    1200,
+			"sql":   Fields{"text": `select "x" [1]`},
+		},
+	}
+}
+
+func newTestSyslogWriter(format SyslogFormat) *SyslogWriter {
+	return &SyslogWriter{
+		Format:   format,
+		Facility: FacilityLocal0,
+		Hostname: "host",
+		AppName:  "app",
+		SDID:     "fields@32473",
+		pid:      42,
+	}
+}
+
+func TestSyslogFormat(t *testing.T) {
+	t.Run("rfc5424", func(t *testing.T) {
+		w := newTestSyslogWriter(RFC5424)
+
+		expected := `<132>1 2024-05-06T12:30:45.123456Z host app 42 db ` +
+			`[fields@32473 ms="1200" sql.text="select \"x\" [1\]" table="users"] slow query`
+		require.Equal(t, expected, w.format(testSyslogRecord()))
+	})
+
+	t.Run("rfc5424 without fields and logger", func(t *testing.T) {
+		w := newTestSyslogWriter(RFC5424)
+		r := Record{Time: time.Date(2024, 5, 6, 12, 30, 45, 0, time.UTC), Level: LevelDebug, Message: "test"}
+
+		require.Equal(t, `<135>1 2024-05-06T12:30:45.000000Z host app 42 - - test`, w.format(r))
+	})
+
+	t.Run("rfc3164", func(t *testing.T) {
+		w := newTestSyslogWriter(RFC3164)
+
+		expected := `<132>May  6 12:30:45 host app[42]: slow query ` +
+			`ms=1200 sql.text="select "x" [1]" table="users"`
+		require.Equal(t, expected, w.format(testSyslogRecord()))
+	})
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	cases := []struct {
+		Level    Level
+		Severity int
+	}{
+		{LevelDebug, 7},
+		{LevelInfo, 6},
+		{LevelWarn, 4},
+		{LevelError, 3},
+		{LevelFatal, 2},
+		{LevelInvalid, 5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Level.String(), func(t *testing.T) {
+			require.Equal(t, c.Severity, syslogSeverity(c.Level))
+		})
+	}
+}
+
+func TestSyslogWriterUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	w, err := NewSyslogWriter("udp", pc.LocalAddr().String(), RFC5424)
+	require.NoError(t, err)
+	defer w.Close()
+
+	l := NewLogger()
+	l.SetOut(w)
+
+	_, err = l.Error("test", "key", "value")
+	require.NoError(t, err)
+
+	buf := make([]byte, 2048)
+	require.NoError(t, pc.SetReadDeadline(time.Now().Add(time.Second)))
+
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(buf[:n]), "<11>1 "))
+	require.True(t, strings.HasSuffix(string(buf[:n]), `[fields@32473 key="value"] test`))
+}
+
+func TestSyslogWriterUnixgram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+
+	pc, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+	defer pc.Close()
+
+	w, err := NewSyslogWriter("unixgram", path, RFC3164)
+	require.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("plain line\n"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 2048)
+	require.NoError(t, pc.SetReadDeadline(time.Now().Add(time.Second)))
+
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(buf[:n]), "<14>"))
+	require.True(t, strings.HasSuffix(string(buf[:n]), ": plain line"))
+}
+
+func TestSyslogWriterUnixStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+
+	ln, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	w, err := NewSyslogWriter("unix", path, RFC3164)
+	require.NoError(t, err)
+	defer w.Close()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	r := bufio.NewReader(conn)
+
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(line, "<14>"))
+	require.True(t, strings.HasSuffix(line, ": first\n"))
+
+	line, err = r.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(line, ": second\n"))
+}
+
+func readOctetFrame(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	size, err := r.ReadString(' ')
+	require.NoError(t, err)
+
+	n, err := strconv.Atoi(strings.TrimSpace(size))
+	require.NoError(t, err)
+
+	frame := make([]byte, n)
+	_, err = io.ReadFull(r, frame)
+	require.NoError(t, err)
+
+	return string(frame)
+}
+
+func TestSyslogWriterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	conns := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			conns <- conn
+		}
+	}()
+
+	w, err := NewSyslogWriter("tcp", ln.Addr().String(), RFC5424)
+	require.NoError(t, err)
+	defer w.Close()
+
+	conn := <-conns
+	r := bufio.NewReader(conn)
+
+	t.Run("octet counting", func(t *testing.T) {
+		_, err := w.WriteRecord(Record{Time: time.Now(), Level: LevelInfo, Message: "first"})
+		require.NoError(t, err)
+		_, err = w.WriteRecord(Record{Time: time.Now(), Level: LevelInfo, Message: "second"})
+		require.NoError(t, err)
+
+		require.True(t, strings.HasSuffix(readOctetFrame(t, r), " - - first"))
+		require.True(t, strings.HasSuffix(readOctetFrame(t, r), " - - second"))
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		require.NoError(t, conn.Close())
+
+		var next net.Conn
+
+		require.Eventually(t, func() bool {
+			_, _ = w.WriteRecord(Record{Time: time.Now(), Level: LevelInfo, Message: "again"})
+
+			select {
+			case next = <-conns:
+				return true
+			default:
+				return false
+			}
+		}, 2*time.Second, 10*time.Millisecond)
+
+		defer next.Close()
+
+		require.True(t, strings.HasSuffix(readOctetFrame(t, bufio.NewReader(next)), " - - again"))
+	})
+}
+
+func TestSyslogWriterLocalMissing(t *testing.T) {
+	original := syslogSockets
+	defer func() { syslogSockets = original }()
+
+	syslogSockets = []string{filepath.Join(t.TempDir(), "missing.sock")}
+
+	_, err := NewSyslogWriter("", "", RFC5424)
+	require.ErrorIs(t, err, ErrNoSyslog)
+}
@@ -0,0 +1,59 @@
+package log
+
+import "context"
+
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// TraceExtractor returns the trace and span id of the span active in ctx, or
+// empty strings if there is none. It allows plugging in a tracing library
+// without this package depending on it.
+type TraceExtractor func(ctx context.Context) (traceID, spanID string)
+
+type traceContextKey struct{}
+
+type traceIDs struct {
+	traceID string
+	spanID  string
+}
+
+// ContextWithTrace stores trace and span ids in ctx for the default
+// TraceExtractor.
+func ContextWithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, traceIDs{traceID: traceID, spanID: spanID})
+}
+
+func TraceFromContext(ctx context.Context) (string, string) {
+	ids, _ := ctx.Value(traceContextKey{}).(traceIDs)
+	return ids.traceID, ids.spanID
+}
+
+// SetTraceExtractor sets the function reading trace and span ids from the
+// context of loggers returned by WithContext. TraceFromContext is used if nil.
+func (l *Logger) SetTraceExtractor(extract TraceExtractor) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.trace = extract
+}
+
+// WithContext returns a logger adding the trace_id and span_id found in ctx
+// to every record.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	c := l.child()
+	c.ctx = ctx
+
+	return c
+}
+
+func extractTrace(ctx context.Context, extract TraceExtractor) (string, string) {
+	if extract == nil {
+		extract = TraceFromContext
+	}
+
+	return extract(ctx)
+}
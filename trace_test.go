@@ -0,0 +1,95 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceFromContext(t *testing.T) {
+	traceID, spanID := TraceFromContext(context.Background())
+	require.Empty(t, traceID)
+	require.Empty(t, spanID)
+
+	ctx := ContextWithTrace(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+
+	traceID, spanID = TraceFromContext(ctx)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	require.Equal(t, "00f067aa0ba902b7", spanID)
+}
+
+func TestLoggerWithContext(t *testing.T) {
+	ctx := ContextWithTrace(context.Background(), "abc", "def")
+
+	t.Run("text", func(t *testing.T) {
+		l := NewLogger()
+		buf := &bytes.Buffer{}
+		l.SetOut(buf)
+		l.SetColor(false)
+
+		_, _ = l.WithContext(ctx).Info("test", "key", "value")
+		require.Contains(t, buf.String(), `key="value" span_id="def" trace_id="abc"`)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		l := NewLogger()
+		buf := &bytes.Buffer{}
+		l.SetOut(buf)
+		l.SetHandler(JSONHandler)
+
+		_, _ = l.WithContext(ctx).Named("db").WithGroup("query").Info("test", "rows", 2)
+
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		require.Equal(t, "abc", got["trace_id"])
+		require.Equal(t, "def", got["span_id"])
+		require.Equal(t, map[string]interface{}{"rows": float64(2)}, got["query"])
+	})
+
+	t.Run("without trace", func(t *testing.T) {
+		l := NewLogger()
+		buf := &bytes.Buffer{}
+		l.SetOut(buf)
+
+		_, _ = l.WithContext(context.Background()).Info("test")
+		require.NotContains(t, buf.String(), "trace_id")
+	})
+
+	t.Run("record writer", func(t *testing.T) {
+		l := NewLogger()
+		records := &recordSlice{}
+		l.SetOut(records)
+
+		_, _ = l.WithContext(ctx).Info("test")
+		require.Len(t, *records, 1)
+		require.Equal(t, "abc", (*records)[0].Fields[TraceIDKey])
+		require.Equal(t, "def", (*records)[0].Fields[SpanIDKey])
+	})
+}
+
+func TestLoggerSetTraceExtractor(t *testing.T) {
+	type spanKey struct{}
+
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetColor(false)
+	l.SetTraceExtractor(func(ctx context.Context) (string, string) {
+		span, _ := ctx.Value(spanKey{}).(string)
+		return "trace-" + span, span
+	})
+
+	ctx := context.WithValue(context.Background(), spanKey{}, "1")
+
+	_, _ = l.Named("child").WithContext(ctx).Info("test")
+	require.Contains(t, buf.String(), `span_id="1" trace_id="trace-1"`)
+
+	buf.Reset()
+	l.SetTraceExtractor(nil)
+
+	_, _ = l.WithContext(ContextWithTrace(ctx, "abc", "def")).Info("test")
+	require.Contains(t, buf.String(), `span_id="def" trace_id="abc"`)
+}
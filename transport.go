@@ -0,0 +1,257 @@
+package log
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var DefaultRedactedParams []string = []string{
+	"access_token",
+	"api_key",
+	"apikey",
+	"key",
+	"password",
+	"secret",
+	"signature",
+	"token",
+}
+
+type Transport struct {
+	Base   http.RoundTripper
+	Logger *Logger
+
+	// RedactedParams lists query parameters whose values are replaced before
+	// the URL is logged. DefaultRedactedParams is used if nil.
+	RedactedParams []string
+
+	// MaxRetries retries requests failing with a transport error if their
+	// body can be replayed and their context is not done. RetryBackoff is
+	// the delay before the first retry and doubles for every further one.
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	// LogBodies logs request and response bodies up to MaxBodySize bytes
+	// at LevelDebug. Bodies are captured while they are read and logged once
+	// they are read to the end or closed.
+	LogBodies   bool
+	MaxBodySize int
+}
+
+func NewTransport(l *Logger, base http.RoundTripper) *Transport {
+	return &Transport{
+		Base:         base,
+		Logger:       l,
+		RetryBackoff: 100 * time.Millisecond,
+		MaxBodySize:  4096,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	target := t.redactURL(req.URL)
+
+	logBodies := t.logBodies()
+
+	// Every attempt sends the body again, so replayed bodies are logged too.
+	requestBody := func(body io.ReadCloser) io.ReadCloser {
+		if !logBodies || body == nil || body == http.NoBody {
+			return body
+		}
+
+		return t.logBody(body, "http request body", "method", req.Method, "url", target)
+	}
+
+	if logBodies && req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(req.Context())
+		req.Body = requestBody(req.Body)
+	}
+
+	var (
+		resp    *http.Response
+		err     error
+		retries int
+	)
+
+	for {
+		resp, err = t.base().RoundTrip(req)
+		if err == nil || retries >= t.MaxRetries || !canRetry(req) || !t.wait(req, retries) {
+			break
+		}
+
+		retries++
+
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				break
+			}
+
+			req = req.Clone(req.Context())
+			req.Body = requestBody(body)
+		}
+	}
+
+	args := []interface{}{
+		"method", req.Method,
+		"url", target,
+		"duration", time.Since(start),
+		"retries", retries,
+	}
+
+	if err != nil {
+		_, _ = t.Logger.Error("http request failed", append(args, "error", err.Error())...)
+		return nil, err
+	}
+
+	args = append(args, "status", resp.StatusCode)
+	_, _ = t.Logger.log(levelForStatus(resp.StatusCode), "http request", args...)
+
+	// Upgraded connections keep a writable body which must not be wrapped.
+	if logBodies && resp.Body != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Body = t.logBody(
+			resp.Body,
+			"http response body",
+			"method", req.Method,
+			"url", target,
+			"status", resp.StatusCode,
+		)
+	}
+
+	return resp, nil
+}
+
+// wait sleeps before retry number retries+1 and reports whether the request
+// may still be retried.
+func (t *Transport) wait(req *http.Request, retries int) bool {
+	ctx := req.Context()
+
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if t.RetryBackoff <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(t.RetryBackoff << retries)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+
+	return t.Base
+}
+
+func (t *Transport) logBodies() bool {
+	return t.LogBodies && t.MaxBodySize > 0 && evalLevel(LevelDebug, t.Logger.GetLevel())
+}
+
+func (t *Transport) logBody(body io.ReadCloser, msg string, args ...interface{}) io.ReadCloser {
+	return &loggedBody{
+		ReadCloser: body,
+		max:        t.MaxBodySize,
+		log: func(captured string) {
+			_, _ = t.Logger.Debug(msg, append(args, "body", captured)...)
+		},
+	}
+}
+
+// loggedBody captures up to max bytes of a body as it is read and logs them
+// once the body is read to the end or closed.
+type loggedBody struct {
+	io.ReadCloser
+	max int
+	log func(captured string)
+
+	mu     sync.Mutex
+	buf    []byte
+	logged bool
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	if rest := b.max - len(b.buf); rest > 0 {
+		b.buf = append(b.buf, p[:min(n, rest)]...)
+	}
+	b.mu.Unlock()
+
+	if err == io.EOF {
+		b.flush()
+	}
+
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	b.flush()
+	return b.ReadCloser.Close()
+}
+
+func (b *loggedBody) flush() {
+	b.mu.Lock()
+	if b.logged {
+		b.mu.Unlock()
+		return
+	}
+
+	b.logged = true
+	captured := string(b.buf)
+	b.mu.Unlock()
+
+	b.log(captured)
+}
+
+func (t *Transport) redactURL(u *url.URL) string {
+	params := t.RedactedParams
+	if params == nil {
+		params = DefaultRedactedParams
+	}
+
+	redacted := *u
+
+	// The query is only rewritten if needed as encoding it sorts and
+	// escapes the parameters again.
+	changed := false
+
+	query := redacted.Query()
+	for key := range query {
+		for _, param := range params {
+			if strings.EqualFold(key, param) {
+				query.Set(key, "REDACTED")
+				changed = true
+			}
+		}
+	}
+
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+
+	return redacted.Redacted()
+}
+
+func canRetry(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
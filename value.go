@@ -0,0 +1,116 @@
+package log
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// LogValuer is implemented by types controlling their own representation in
+// log records. LogValue may return a scalar or Fields for a set of sub-fields.
+// It is only called for records which are actually emitted.
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+const maxLogValuerDepth = 10
+
+func resolveFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		out[k] = resolveValue(v)
+	}
+
+	return out
+}
+
+func resolveValue(v interface{}) (out interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = fmt.Sprintf("!PANIC: %v", r)
+		}
+	}()
+
+	for i := 0; i < maxLogValuerDepth; i++ {
+		lv, ok := v.(LogValuer)
+		if !ok || isNilPointer(v) {
+			break
+		}
+
+		v = lv.LogValue()
+	}
+
+	if isNilPointer(v) {
+		return v
+	}
+
+	switch t := v.(type) {
+	case Fields:
+		return Fields(resolveFields(t))
+	case error:
+		// Errors are kept so handlers can highlight them, jsonArg converts
+		// them to their message.
+		return v
+	case encoding.TextMarshaler:
+		b, err := t.MarshalText()
+		if err != nil {
+			return fmt.Sprintf("!ERROR: %v", err)
+		}
+
+		return string(b)
+	case json.Marshaler:
+		return v
+	case fmt.Stringer:
+		return t.String()
+	default:
+		return v
+	}
+}
+
+// jsonArgs returns fields prepared for encoding/json, see jsonArg.
+func jsonArgs(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		out[k] = jsonArg(v)
+	}
+
+	return out
+}
+
+// jsonArg replaces errors by their message as encoding/json would encode
+// most of them as an empty object.
+func jsonArg(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Marshaler:
+		return v
+	case error:
+		if isNilPointer(v) {
+			return nil
+		}
+
+		return t.Error()
+	case Fields:
+		return Fields(jsonArgs(t))
+	default:
+		return v
+	}
+}
+
+func isNilPointer(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+
+	rv := reflect.ValueOf(v)
+
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
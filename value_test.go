@@ -0,0 +1,124 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testUser struct {
+	Name     string
+	Password string
+	calls    *int
+}
+
+func (u testUser) LogValue() interface{} {
+	*u.calls++
+	return Group("name", u.Name)
+}
+
+type testID int
+
+func (id testID) LogValue() interface{} {
+	return int(id) * 10
+}
+
+type testChain struct{}
+
+func (testChain) LogValue() interface{} {
+	return testID(4)
+}
+
+type testStringer struct{}
+
+func (*testStringer) String() string {
+	return "stringer"
+}
+
+type testPanicker struct{}
+
+func (testPanicker) LogValue() interface{} {
+	panic("boom")
+}
+
+type testJSON struct{}
+
+func (testJSON) MarshalJSON() ([]byte, error) {
+	return []byte(`"json"`), nil
+}
+
+func (testJSON) String() string {
+	return "string"
+}
+
+func TestResolveValue(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+
+	var nilStringer *testStringer
+
+	cases := []struct {
+		Name     string
+		Value    interface{}
+		Expected interface{}
+	}{
+		{Name: "nil", Value: nil, Expected: nil},
+		{Name: "scalar", Value: 42, Expected: 42},
+		{Name: "log valuer", Value: testID(4), Expected: 40},
+		{Name: "log valuer chain", Value: testChain{}, Expected: 40},
+		{Name: "text marshaler", Value: ts, Expected: "2024-05-06T12:00:00Z"},
+		{Name: "stringer", Value: &testStringer{}, Expected: "stringer"},
+		{Name: "nil stringer", Value: nilStringer, Expected: nilStringer},
+		{Name: "duration", Value: 1500 * time.Millisecond, Expected: "1.5s"},
+		{Name: "error kept", Value: errors.New("failed"), Expected: errors.New("failed")},
+		{Name: "json marshaler kept", Value: testJSON{}, Expected: testJSON{}},
+		{Name: "panic", Value: testPanicker{}, Expected: "!PANIC: boom"},
+		{Name: "fields", Value: Group("id", testID(1)), Expected: Fields{"id": 10}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			require.Equal(t, c.Expected, resolveValue(c.Value))
+		})
+	}
+}
+
+func TestLoggerLogValuer(t *testing.T) {
+	l := NewLogger()
+	buf := &bytes.Buffer{}
+	l.SetOut(buf)
+	l.SetColor(false)
+
+	calls := 0
+	user := testUser{Name: "anton", Password: "secret", calls: &calls}
+
+	t.Run("text", func(t *testing.T) {
+		defer buf.Reset()
+
+		_, _ = l.Info("test", "user", user)
+		require.Contains(t, buf.String(), `msg="test" user.name="anton"`)
+		require.NotContains(t, buf.String(), "secret")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		defer buf.Reset()
+
+		l.SetHandler(JSONHandler)
+		defer l.SetHandler(TextHandler)
+
+		_, _ = l.With("user", user).Info("test")
+		require.Contains(t, buf.String(), `"msg":"test","user":{"name":"anton"}`)
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		defer buf.Reset()
+
+		calls = 0
+		_, _ = l.Debug("test", "user", user)
+		_, _ = l.With("user", user).Debug("test")
+
+		require.Zero(t, calls)
+	})
+}
@@ -0,0 +1,79 @@
+package log
+
+import (
+	"errors"
+	"io"
+)
+
+// WriteErrorHandler is called with the error and the record if writing to
+// the output fails. It must not log to the failing logger.
+type WriteErrorHandler func(err error, r Record)
+
+type WriteStats struct {
+	// Failed counts records the output failed to write.
+	Failed uint64
+
+	// Fallback counts failed records written to the fallback output and
+	// FallbackFailed those the fallback output failed to write as well.
+	Fallback       uint64
+	FallbackFailed uint64
+}
+
+func (l *Logger) SetWriteErrorHandler(h WriteErrorHandler) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onWriteError = h
+}
+
+// SetFallbackOut sets an output, e.g. os.Stderr, receiving records the
+// output failed to write. Passing nil disables the fallback.
+func (l *Logger) SetFallbackOut(w io.Writer) {
+	r := l.root()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = w
+}
+
+func (l *Logger) WriteStats() WriteStats {
+	r := l.root()
+
+	return WriteStats{
+		Failed:         r.writeFailures.Load(),
+		Fallback:       r.fallbackWrites.Load(),
+		FallbackFailed: r.fallbackFailures.Load(),
+	}
+}
+
+// handleWriteError reports err and writes out to the fallback output. The
+// error is only returned if there is no fallback or it failed as well.
+func (l *Logger) handleWriteError(out *msg, err error) (int, error) {
+	l.writeFailures.Add(1)
+
+	l.mu.RLock()
+	handler := l.onWriteError
+	fallback := l.fallback
+	l.mu.RUnlock()
+
+	if handler != nil {
+		handler(err, out.record())
+	}
+
+	if fallback == nil {
+		return 0, err
+	}
+
+	n, fallbackErr := l.writeTo(fallback, out)
+	if fallbackErr != nil {
+		l.fallbackFailures.Add(1)
+		return 0, errors.Join(err, fallbackErr)
+	}
+
+	l.fallbackWrites.Add(1)
+
+	return n, nil
+}
@@ -0,0 +1,122 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct {
+	err error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestLoggerWriteError(t *testing.T) {
+	l := NewLogger()
+	l.SetOut(&failingWriter{err: syscall.EPIPE})
+
+	n, err := l.Info("test")
+	require.ErrorIs(t, err, syscall.EPIPE)
+	require.Zero(t, n)
+	require.Equal(t, WriteStats{Failed: 1}, l.WriteStats())
+}
+
+func TestLoggerSetWriteErrorHandler(t *testing.T) {
+	l := NewLogger()
+	l.SetOut(&failingWriter{err: syscall.ENOSPC})
+
+	var (
+		gotErr    error
+		gotRecord Record
+	)
+
+	l.Named("db").SetWriteErrorHandler(func(err error, r Record) {
+		gotErr = err
+		gotRecord = r
+	})
+
+	l.SetExitFunc(func(int) {})
+	l.Named("db").Fatal("test", "key", "value")
+
+	require.ErrorIs(t, gotErr, syscall.ENOSPC)
+	require.Equal(t, LevelFatal, gotRecord.Level)
+	require.Equal(t, "db", gotRecord.Logger)
+	require.Equal(t, "test", gotRecord.Message)
+	require.Equal(t, map[string]interface{}{"key": "value"}, gotRecord.Fields)
+	require.Equal(t, uint64(1), l.WriteStats().Failed)
+}
+
+func TestLoggerSetFallbackOut(t *testing.T) {
+	t.Run("fallback written", func(t *testing.T) {
+		l := NewLogger()
+		l.SetOut(&failingWriter{err: syscall.EPIPE})
+		l.SetColor(false)
+
+		fallback := &bytes.Buffer{}
+		l.SetFallbackOut(fallback)
+
+		n, err := l.Warn("test", "key", "value")
+		require.NoError(t, err)
+		require.Equal(t, fallback.Len(), n)
+		require.Contains(t, fallback.String(), `level=WRN msg="test" key="value"`)
+		require.Equal(t, WriteStats{Failed: 1, Fallback: 1}, l.WriteStats())
+	})
+
+	t.Run("fallback record writer", func(t *testing.T) {
+		l := NewLogger()
+		l.SetOut(&failingWriter{err: syscall.EPIPE})
+
+		records := &recordSlice{}
+		l.SetFallbackOut(records)
+
+		_, err := l.Info("test")
+		require.NoError(t, err)
+		require.Len(t, *records, 1)
+		require.Equal(t, "test", (*records)[0].Message)
+	})
+
+	t.Run("fallback failed", func(t *testing.T) {
+		l := NewLogger()
+		l.SetOut(&failingWriter{err: syscall.EPIPE})
+
+		fallbackErr := errors.New("fallback broken")
+		l.SetFallbackOut(&failingWriter{err: fallbackErr})
+
+		_, err := l.Error("test")
+		require.ErrorIs(t, err, syscall.EPIPE)
+		require.ErrorIs(t, err, fallbackErr)
+		require.Equal(t, WriteStats{Failed: 1, FallbackFailed: 1}, l.WriteStats())
+	})
+
+	t.Run("primary healthy", func(t *testing.T) {
+		l := NewLogger()
+		l.SetOut(&bytes.Buffer{})
+
+		fallback := &bytes.Buffer{}
+		l.SetFallbackOut(fallback)
+
+		_, err := l.Info("test")
+		require.NoError(t, err)
+		require.Empty(t, fallback.String())
+		require.Equal(t, WriteStats{}, l.WriteStats())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		l := NewLogger()
+		l.SetOut(&failingWriter{err: syscall.EPIPE})
+
+		fallback := &bytes.Buffer{}
+		l.SetFallbackOut(fallback)
+		l.SetFallbackOut(nil)
+
+		_, err := l.Info("test")
+		require.ErrorIs(t, err, syscall.EPIPE)
+		require.Empty(t, fallback.String())
+	})
+}